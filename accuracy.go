@@ -0,0 +1,276 @@
+package xbrl
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"math/big"
+	"strconv"
+	"strings"
+)
+
+// ErrUnknownAccuracy is returned when the accuracy of a fact can't be determined,
+// because it has neither a decimals nor a precision attribute, or because its decimals can't be inferred from precision="0".
+var ErrUnknownAccuracy = errors.New("accuracy of fact is unknown")
+
+// Accuracy is the parsed value of a decimals or precision attribute.
+// https://www.xbrl.org/Specification/XBRL-2.1/REC-2003-12-31/XBRL-2.1-REC-2003-12-31+corrected-errata-2013-02-20.html#_4.6.3
+type Accuracy struct {
+	// Value is the number of decimal places (for decimals) or significant figures (for precision).
+	// It's only meaningful if Infinite is false.
+	Value int
+
+	// Infinite is true for the special value "INF", which means the value is exact.
+	Infinite bool
+}
+
+// ParseAccuracy parses the value of a decimals or precision attribute, which is either an integer or "INF".
+func ParseAccuracy(value string) (Accuracy, error) {
+	value = strings.TrimSpace(value)
+	if value == "INF" {
+		return Accuracy{Infinite: true}, nil
+	}
+
+	parsed, err := strconv.Atoi(value)
+	if err != nil {
+		return Accuracy{}, fmt.Errorf("invalid accuracy: %q", value)
+	}
+
+	return Accuracy{Value: parsed}, nil
+}
+
+// String returns the accuracy as it would be written in a decimals or precision attribute.
+func (a Accuracy) String() string {
+	if a.Infinite {
+		return "INF"
+	}
+
+	return strconv.Itoa(a.Value)
+}
+
+// DecimalsValue returns the decimals of a numeric fact.
+// If the fact has a precision attribute instead, decimals is inferred from it and the fact's value following section 4.6.6 of the spec.
+// Fraction facts are exact, so their decimals is always infinite.
+// https://www.xbrl.org/Specification/XBRL-2.1/REC-2003-12-31/XBRL-2.1-REC-2003-12-31+corrected-errata-2013-02-20.html#_4.6.6
+func (f Fact) DecimalsValue() (Accuracy, error) {
+	switch f.Type() {
+	case FactTypeFraction:
+		return Accuracy{Infinite: true}, nil
+	case FactTypeNonFraction:
+	default:
+		return Accuracy{}, ErrNonNumericFactType
+	}
+
+	if f.Decimals != nil {
+		return ParseAccuracy(*f.Decimals)
+	}
+
+	if f.Precision == nil {
+		return Accuracy{}, ErrUnknownAccuracy
+	}
+
+	precision, err := ParseAccuracy(*f.Precision)
+	if err != nil {
+		return Accuracy{}, err
+	}
+
+	value, err := f.RatValue()
+	if err != nil {
+		return Accuracy{}, err
+	}
+
+	switch {
+	case precision.Infinite || value.Sign() == 0:
+		return Accuracy{Infinite: true}, nil
+	case precision.Value == 0:
+		return Accuracy{}, ErrUnknownAccuracy
+	default:
+		return Accuracy{Value: precision.Value - decimalExponent(value) - 1}, nil
+	}
+}
+
+// PrecisionValue returns the precision of a numeric fact.
+// If the fact has a decimals attribute instead, precision is inferred from it and the fact's value following section 4.6.7 of the spec.
+// Fraction facts are exact, so their precision is always infinite.
+// https://www.xbrl.org/Specification/XBRL-2.1/REC-2003-12-31/XBRL-2.1-REC-2003-12-31+corrected-errata-2013-02-20.html#_4.6.7
+func (f Fact) PrecisionValue() (Accuracy, error) {
+	switch f.Type() {
+	case FactTypeFraction:
+		return Accuracy{Infinite: true}, nil
+	case FactTypeNonFraction:
+	default:
+		return Accuracy{}, ErrNonNumericFactType
+	}
+
+	if f.Precision != nil {
+		return ParseAccuracy(*f.Precision)
+	}
+
+	if f.Decimals == nil {
+		return Accuracy{}, ErrUnknownAccuracy
+	}
+
+	decimals, err := ParseAccuracy(*f.Decimals)
+	if err != nil {
+		return Accuracy{}, err
+	}
+
+	if decimals.Infinite {
+		return decimals, nil
+	}
+
+	value, err := f.RatValue()
+	if err != nil {
+		return Accuracy{}, err
+	}
+
+	if value.Sign() == 0 {
+		return Accuracy{Value: 0}, nil
+	}
+
+	precision := decimalExponent(value) + 1 + decimals.Value
+	if precision < 0 {
+		precision = 0
+	}
+
+	return Accuracy{Value: precision}, nil
+}
+
+// RoundedValue returns the exact value of a numeric fact rounded to its decimals (see DecimalsValue()).
+// Facts reported at different accuracies (ie in thousands and in units) can be compared by rounding both to the lowest of their decimals.
+func (f Fact) RoundedValue() (*big.Rat, error) {
+	decimals, err := f.DecimalsValue()
+	if err != nil {
+		return nil, err
+	}
+
+	value, err := f.RatValue()
+	if err != nil {
+		return nil, err
+	}
+
+	return RoundRat(value, decimals), nil
+}
+
+// RoundRat rounds value to the given decimals using round half to even, the rounding XBRL uses for the decimals attribute.
+// A negative decimals rounds to the left of the decimal point, ie -3 rounds to thousands.
+// If decimals is infinite, a copy of value is returned.
+//
+// Decimals comes from the document, which could have any integer in it, so rounding doesn't depend on the size of decimals
+// when it's beyond the magnitude of value: a value with at most that many decimal places is returned unchanged,
+// and a value that's less than half of 10^-decimals is rounded to 0, without ever computing 10^decimals.
+func RoundRat(value *big.Rat, decimals Accuracy) *big.Rat {
+	if decimals.Infinite {
+		return new(big.Rat).Set(value)
+	}
+
+	if value.Sign() == 0 || decimals.Value <= -(decimalExponent(value)+2) {
+		return new(big.Rat)
+	}
+
+	if places, ok := decimalPlaces(value); ok && decimals.Value >= places {
+		return new(big.Rat).Set(value)
+	}
+
+	// Scale the value so the digit to round to is the ones digit, round it to an integer, then scale it back.
+	scale := new(big.Rat).SetInt(new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(abs(decimals.Value))), nil))
+	scaled := new(big.Rat).Set(value)
+	if decimals.Value >= 0 {
+		scaled.Mul(scaled, scale)
+	} else {
+		scaled.Quo(scaled, scale)
+	}
+
+	rounded := new(big.Rat).SetInt(roundHalfEven(scaled))
+	if decimals.Value >= 0 {
+		return rounded.Quo(rounded, scale)
+	}
+
+	return rounded.Mul(rounded, scale)
+}
+
+// roundHalfEven rounds value to the nearest integer, and ties to the nearest even integer.
+func roundHalfEven(value *big.Rat) *big.Int {
+	quotient, remainder := new(big.Int).QuoRem(value.Num(), value.Denom(), new(big.Int))
+
+	// Compare twice the remainder with the denominator to see if the fractional part is below, at or above one half.
+	twiceRemainder := new(big.Int).Abs(remainder)
+	twiceRemainder.Lsh(twiceRemainder, 1)
+	comparison := twiceRemainder.Cmp(value.Denom())
+
+	if comparison > 0 || (comparison == 0 && quotient.Bit(0) == 1) {
+		if value.Sign() < 0 {
+			return quotient.Sub(quotient, big.NewInt(1))
+		}
+
+		return quotient.Add(quotient, big.NewInt(1))
+	}
+
+	return quotient
+}
+
+// decimalExponent returns the exponent of the most significant digit of a non-zero value, which is floor(log10(|value|)).
+// For example, it's 2 for 727.4 and -2 for 0.064.
+func decimalExponent(value *big.Rat) int {
+	absolute := new(big.Rat).Abs(value)
+	float, _ := absolute.Float64()
+	exponent := int(math.Floor(math.Log10(float)))
+
+	// The float64 estimate can be off by one near powers of 10 (or way off if it under or overflows), so correct it exactly.
+	if math.IsInf(float, 0) || float == 0 {
+		exponent = len(absolute.Num().String()) - len(absolute.Denom().String())
+	}
+
+	for powerOfTen(exponent).Cmp(absolute) > 0 {
+		exponent--
+	}
+
+	for powerOfTen(exponent+1).Cmp(absolute) <= 0 {
+		exponent++
+	}
+
+	return exponent
+}
+
+// decimalPlaces returns the number of digits after the decimal point of value, which is false if its decimal expansion doesn't end.
+// That's the case unless the denominator of value (in lowest terms) is 2^a * 5^b, which has max(a, b) decimal places.
+func decimalPlaces(value *big.Rat) (int, bool) {
+	denominator := new(big.Int).Set(value.Denom())
+	twos := int(denominator.TrailingZeroBits())
+	denominator.Rsh(denominator, uint(twos))
+
+	fives := 0
+	for denominator.Cmp(big.NewInt(1)) != 0 {
+		quotient, remainder := new(big.Int).QuoRem(denominator, big.NewInt(5), new(big.Int))
+		if remainder.Sign() != 0 {
+			return 0, false
+		}
+
+		denominator = quotient
+		fives++
+	}
+
+	if twos > fives {
+		return twos, true
+	}
+
+	return fives, true
+}
+
+// powerOfTen returns 10^exponent as an exact big.Rat.
+func powerOfTen(exponent int) *big.Rat {
+	power := new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(abs(exponent))), nil)
+	if exponent < 0 {
+		return new(big.Rat).SetFrac(big.NewInt(1), power)
+	}
+
+	return new(big.Rat).SetInt(power)
+}
+
+func abs(value int) int {
+	if value < 0 {
+		return -value
+	}
+
+	return value
+}
@@ -0,0 +1,124 @@
+package xbrl
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseAccuracy(t *testing.T) {
+	accuracy, err := ParseAccuracy("INF")
+	require.NoError(t, err)
+	assert.Equal(t, Accuracy{Infinite: true}, accuracy)
+	assert.Equal(t, "INF", accuracy.String())
+
+	accuracy, err = ParseAccuracy(" -3 ")
+	require.NoError(t, err)
+	assert.Equal(t, Accuracy{Value: -3}, accuracy)
+	assert.Equal(t, "-3", accuracy.String())
+
+	_, err = ParseAccuracy("inf")
+	assert.Error(t, err)
+}
+
+func TestFactAccuracy(t *testing.T) {
+	tests := []struct {
+		value     string
+		decimals  *string
+		precision *string
+
+		expectedDecimals  Accuracy
+		expectedPrecision Accuracy
+		expectedRounded   string
+	}{
+		// Examples from sections 4.6.6 and 4.6.7 of the spec.
+		{"123", nil, stringPtr("5"), Accuracy{Value: 2}, Accuracy{Value: 5}, "123"},
+		{"0.0123", nil, stringPtr("2"), Accuracy{Value: 3}, Accuracy{Value: 2}, "0.012"},
+		{"-0.0123", nil, stringPtr("4"), Accuracy{Value: 5}, Accuracy{Value: 4}, "-0.0123"},
+		{"123456", stringPtr("-3"), nil, Accuracy{Value: -3}, Accuracy{Value: 3}, "123000"},
+		{"12.3456", stringPtr("2"), nil, Accuracy{Value: 2}, Accuracy{Value: 4}, "12.35"},
+		{"1000", stringPtr("-3"), nil, Accuracy{Value: -3}, Accuracy{Value: 1}, "1000"},
+		{"999", stringPtr("-3"), nil, Accuracy{Value: -3}, Accuracy{Value: 0}, "1000"},
+		{"499", stringPtr("-3"), nil, Accuracy{Value: -3}, Accuracy{Value: 0}, "0"},
+		{"0", stringPtr("2"), nil, Accuracy{Value: 2}, Accuracy{Value: 0}, "0"},
+		{"0", nil, stringPtr("3"), Accuracy{Infinite: true}, Accuracy{Value: 3}, "0"},
+		{"1.5", stringPtr("INF"), nil, Accuracy{Infinite: true}, Accuracy{Infinite: true}, "1.5"},
+
+		// Decimals far beyond the magnitude of the value don't need to be computed.
+		{"123.45", stringPtr("200000000"), nil, Accuracy{Value: 200000000}, Accuracy{Value: 200000003}, "123.45"},
+		{"123.45", stringPtr("-200000000"), nil, Accuracy{Value: -200000000}, Accuracy{Value: 0}, "0"},
+		{"0.5", stringPtr("-1"), nil, Accuracy{Value: -1}, Accuracy{Value: 0}, "0"},
+		{"5", stringPtr("-1"), nil, Accuracy{Value: -1}, Accuracy{Value: 0}, "0"},
+
+		// Round half to even.
+		{"2.5", stringPtr("0"), nil, Accuracy{Value: 0}, Accuracy{Value: 1}, "2"},
+		{"3.5", stringPtr("0"), nil, Accuracy{Value: 0}, Accuracy{Value: 1}, "4"},
+		{"-2500", stringPtr("-3"), nil, Accuracy{Value: -3}, Accuracy{Value: 1}, "-2000"},
+		{"-3500", stringPtr("-3"), nil, Accuracy{Value: -3}, Accuracy{Value: 1}, "-4000"},
+	}
+
+	for _, tc := range tests {
+		fact := Fact{ContextRef: "c1", UnitRef: stringPtr("u1"), Decimals: tc.decimals, Precision: tc.precision, ValueStr: stringPtr(tc.value)}
+
+		decimals, err := fact.DecimalsValue()
+		require.NoError(t, err, tc.value)
+		assert.Equal(t, tc.expectedDecimals, decimals, "decimals of %s", tc.value)
+
+		precision, err := fact.PrecisionValue()
+		require.NoError(t, err, tc.value)
+		assert.Equal(t, tc.expectedPrecision, precision, "precision of %s", tc.value)
+
+		rounded, err := fact.RoundedValue()
+		require.NoError(t, err, tc.value)
+		expected, _ := new(big.Rat).SetString(tc.expectedRounded)
+		assert.Zero(t, expected.Cmp(rounded), "%s rounded to %s is %s", tc.value, decimals, rounded.FloatString(6))
+	}
+
+	t.Run("precision zero", func(t *testing.T) {
+		fact := Fact{ContextRef: "c1", UnitRef: stringPtr("u1"), Precision: stringPtr("0"), ValueStr: stringPtr("123")}
+
+		_, err := fact.DecimalsValue()
+		assert.ErrorIs(t, err, ErrUnknownAccuracy)
+
+		_, err = fact.RoundedValue()
+		assert.ErrorIs(t, err, ErrUnknownAccuracy)
+	})
+
+	t.Run("fraction", func(t *testing.T) {
+		fact := Fact{ContextRef: "c1", UnitRef: stringPtr("u1")}
+		require.NoError(t, fact.setFractionTerms(stringPtr("1"), stringPtr("3")))
+
+		decimals, err := fact.DecimalsValue()
+		require.NoError(t, err)
+		assert.True(t, decimals.Infinite)
+
+		rounded, err := fact.RoundedValue()
+		require.NoError(t, err)
+		assert.Equal(t, "1/3", rounded.String())
+	})
+
+	t.Run("non-numeric", func(t *testing.T) {
+		_, err := Fact{ContextRef: "c1", ValueStr: stringPtr("text")}.DecimalsValue()
+		assert.ErrorIs(t, err, ErrNonNumericFactType)
+	})
+}
+
+func TestDecimalExponent(t *testing.T) {
+	tests := map[string]int{
+		"1":        0,
+		"9.99":     0,
+		"10":       1,
+		"727.4":    2,
+		"-0.064":   -2,
+		"0.1":      -1,
+		"1e-400":   -400,
+		"12.3e400": 401,
+	}
+
+	for value, expected := range tests {
+		rat, _ := new(big.Rat).SetString(value)
+		assert.Equal(t, expected, decimalExponent(rat), value)
+	}
+}
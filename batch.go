@@ -0,0 +1,140 @@
+package xbrl
+
+import (
+	"context"
+	"encoding/xml"
+	"io"
+	"os"
+	"runtime"
+	"sync"
+)
+
+// BatchResult is the result of parsing one document of a batch with ParseFiles or ParseReaders.
+type BatchResult struct {
+	// Index is the position of the document in the paths or readers that were passed in.
+	Index int
+
+	// Path is the path of the document when parsing files, and empty when parsing readers.
+	Path string
+
+	// XBRL is the parsed document. It's only set if Err is nil.
+	XBRL XBRL
+
+	// Err is the error opening or parsing the document, or the context's error if the batch was canceled before the document was parsed.
+	Err error
+}
+
+// BatchOptions configure how ParseFiles and ParseReaders parse documents. The zero value uses the defaults.
+type BatchOptions struct {
+	// Workers is the maximum number of documents that are parsed at the same time.
+	// If it's 0 or less, it defaults to runtime.GOMAXPROCS(0).
+	Workers int
+
+	// CharsetReader is used as the xml.Decoder's CharsetReader to read documents that aren't UTF-8 encoded.
+	// If it's nil, parsing documents that declare another encoding fails, like it does with xml.Unmarshal.
+	CharsetReader func(charset string, input io.Reader) (io.Reader, error)
+}
+
+// ParseFiles parses the XBRL documents at paths in parallel.
+//
+// A result is returned for every path in the same order as paths, whether parsing it failed or not.
+// Canceling ctx stops parsing documents, and the documents that weren't done yet get ctx.Err() as their error.
+func ParseFiles(ctx context.Context, paths []string, options BatchOptions) []BatchResult {
+	results := parseBatch(ctx, len(paths), options, func(index int) (io.ReadCloser, error) {
+		return os.Open(paths[index])
+	})
+
+	for index := range results {
+		results[index].Path = paths[index]
+	}
+
+	return results
+}
+
+// ParseReaders is like ParseFiles, but it parses the XBRL documents read from readers.
+// Readers aren't closed, and readers of documents that aren't parsed because ctx was canceled aren't read at all.
+func ParseReaders(ctx context.Context, readers []io.Reader, options BatchOptions) []BatchResult {
+	return parseBatch(ctx, len(readers), options, func(index int) (io.ReadCloser, error) {
+		return io.NopCloser(readers[index]), nil
+	})
+}
+
+// parseBatch parses count documents in parallel, opening each of them with open, and returns their results by index.
+func parseBatch(ctx context.Context, count int, options BatchOptions, open func(index int) (io.ReadCloser, error)) []BatchResult {
+	workers := options.Workers
+	if workers <= 0 {
+		workers = runtime.GOMAXPROCS(0)
+	}
+
+	if workers > count {
+		workers = count
+	}
+
+	indexes := make(chan int)
+	go func() {
+		defer close(indexes)
+		for index := 0; index < count; index++ {
+			indexes <- index
+		}
+	}()
+
+	// Every worker writes to different indexes of results, so they don't need to be synchronized.
+	results := make([]BatchResult, count)
+	var wg sync.WaitGroup
+	wg.Add(workers)
+	for worker := 0; worker < workers; worker++ {
+		go func() {
+			defer wg.Done()
+			for index := range indexes {
+				processed, err := parseBatchDocument(ctx, index, options, open)
+				results[index] = BatchResult{Index: index, XBRL: processed, Err: err}
+			}
+		}()
+	}
+
+	wg.Wait()
+	return results
+}
+
+// parseBatchDocument opens and parses a single document of a batch, unless ctx is already done.
+func parseBatchDocument(ctx context.Context, index int, options BatchOptions, open func(index int) (io.ReadCloser, error)) (XBRL, error) {
+	if err := ctx.Err(); err != nil {
+		return XBRL{}, err
+	}
+
+	r, err := open(index)
+	if err != nil {
+		return XBRL{}, err
+	}
+	defer r.Close()
+
+	decoder := xml.NewDecoder(contextReader{ctx: ctx, r: r})
+	decoder.CharsetReader = options.CharsetReader
+
+	var processed XBRL
+	if err := decoder.Decode(&processed); err != nil {
+		// Decoding errors can hide the context's error, so check for it first.
+		if ctxErr := ctx.Err(); ctxErr != nil {
+			return XBRL{}, ctxErr
+		}
+
+		return XBRL{}, err
+	}
+
+	return processed, nil
+}
+
+// contextReader is an io.Reader that stops reading once its context is done,
+// so documents that are being parsed when a batch is canceled stop early too.
+type contextReader struct {
+	ctx context.Context
+	r   io.Reader
+}
+
+func (c contextReader) Read(p []byte) (int, error) {
+	if err := c.ctx.Err(); err != nil {
+		return 0, err
+	}
+
+	return c.r.Read(p)
+}
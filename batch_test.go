@@ -0,0 +1,148 @@
+package xbrl
+
+import (
+	"context"
+	"errors"
+	"io"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseFiles(t *testing.T) {
+	t.Run("results in order", func(t *testing.T) {
+		paths := []string{
+			"test_data/aapl-20210327_htm.xml",
+			"test_data/does_not_exist.xml",
+			"test_data/simple_xbrl.xml",
+			"test_data/edgr-2004_10k.xml",
+			"test_data/simple_inline_xbrl.htm",
+		}
+
+		// The 2004 document is encoded as iso-8859-1, but it only has ASCII characters.
+		results := ParseFiles(context.Background(), paths, BatchOptions{
+			Workers: 2,
+			CharsetReader: func(charset string, input io.Reader) (io.Reader, error) {
+				return input, nil
+			},
+		})
+		require.Len(t, results, len(paths))
+		for index, result := range results {
+			assert.Equal(t, index, result.Index)
+			assert.Equal(t, paths[index], result.Path)
+		}
+
+		require.NoError(t, results[0].Err)
+		assert.Len(t, results[0].XBRL.Facts, 1070)
+
+		assert.True(t, errors.Is(results[1].Err, os.ErrNotExist))
+
+		require.NoError(t, results[2].Err)
+		assert.NotEmpty(t, results[2].XBRL.Facts)
+
+		require.NoError(t, results[3].Err)
+		assert.NotEmpty(t, results[3].XBRL.Facts)
+
+		// Inline XBRL documents aren't XBRL documents, so they don't have any facts or contexts on their own.
+		require.NoError(t, results[4].Err)
+		assert.Empty(t, results[4].XBRL.ContextsByID)
+	})
+
+	t.Run("default workers", func(t *testing.T) {
+		results := ParseFiles(context.Background(), []string{"test_data/simple_xbrl.xml"}, BatchOptions{})
+		require.Len(t, results, 1)
+		assert.NoError(t, results[0].Err)
+	})
+
+	t.Run("no files", func(t *testing.T) {
+		assert.Empty(t, ParseFiles(context.Background(), nil, BatchOptions{Workers: 4}))
+	})
+
+	t.Run("canceled", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+
+		results := ParseFiles(ctx, []string{"test_data/simple_xbrl.xml", "test_data/aapl-20210327_htm.xml"}, BatchOptions{Workers: 1})
+		require.Len(t, results, 2)
+		for _, result := range results {
+			assert.True(t, errors.Is(result.Err, context.Canceled))
+		}
+	})
+}
+
+func TestParseReaders(t *testing.T) {
+	documents := []string{
+		// language=xml
+		`<xbrl><context id="c1"/><ci:assets contextRef="c1">1</ci:assets></xbrl>`,
+		// language=xml
+		`<xbrl><ci:assets contextRef="c1">1</ci:assets`,
+		// language=xml
+		`<xbrl><ci:assets contextRef="c1">1</ci:assets><ci:liabilities contextRef="c1">2</ci:liabilities></xbrl>`,
+	}
+
+	t.Run("results in order", func(t *testing.T) {
+		readers := make([]io.Reader, len(documents))
+		for index, document := range documents {
+			readers[index] = strings.NewReader(document)
+		}
+
+		results := ParseReaders(context.Background(), readers, BatchOptions{Workers: 3})
+		require.Len(t, results, len(documents))
+
+		require.NoError(t, results[0].Err)
+		assert.Len(t, results[0].XBRL.Facts, 1)
+		assert.Contains(t, results[0].XBRL.ContextsByID, "c1")
+
+		assert.Error(t, results[1].Err)
+		assert.Empty(t, results[1].Path)
+
+		require.NoError(t, results[2].Err)
+		assert.Len(t, results[2].XBRL.Facts, 2)
+	})
+
+	t.Run("canceled while parsing", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+
+		// The document is cut in two, and the batch is canceled when the first half has been read.
+		r := &cancelingReader{chunks: []string{documents[2][:20], documents[2][20:]}, cancel: cancel}
+		results := ParseReaders(ctx, []io.Reader{r}, BatchOptions{Workers: 1})
+		require.Len(t, results, 1)
+		assert.True(t, errors.Is(results[0].Err, context.Canceled))
+	})
+}
+
+// cancelingReader is an io.Reader that returns one chunk per read, and calls cancel after the first one.
+type cancelingReader struct {
+	chunks []string
+	cancel context.CancelFunc
+}
+
+func (c *cancelingReader) Read(p []byte) (int, error) {
+	if len(c.chunks) == 0 {
+		return 0, io.EOF
+	}
+
+	n := copy(p, c.chunks[0])
+	c.chunks = c.chunks[1:]
+	c.cancel()
+	return n, nil
+}
+
+func BenchmarkParseFiles(b *testing.B) {
+	paths := make([]string, 16)
+	for index := range paths {
+		paths[index] = "test_data/aapl-20210327_htm.xml"
+	}
+
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		for _, result := range ParseFiles(context.Background(), paths, BatchOptions{}) {
+			if result.Err != nil {
+				b.Fatal(result.Err)
+			}
+		}
+	}
+}
@@ -0,0 +1,641 @@
+package xbrl
+
+import (
+	"fmt"
+	"math/big"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// The arcroles of calculation relationships between a total and one of the items it sums.
+const (
+	// ArcRoleSummationItem is the summation-item arcrole of XBRL 2.1.
+	// https://www.xbrl.org/Specification/XBRL-2.1/REC-2003-12-31/XBRL-2.1-REC-2003-12-31+corrected-errata-2013-02-20.html#_5.2.5.2
+	ArcRoleSummationItem = "http://www.xbrl.org/2003/arcrole/summation-item"
+
+	// ArcRoleSummationItem11 is the summation-item arcrole of Calculations 1.1, which replaces the XBRL 2.1 one in newer taxonomies.
+	// https://www.xbrl.org/Specification/calculation-1.1/REC-2023-02-22/calculation-1.1-REC-2023-02-22.html
+	ArcRoleSummationItem11 = "https://xbrl.org/2023/arcrole/summation-item"
+)
+
+// CalculationLinkbase is a calculation linkbase document, which is part of a taxonomy rather than of the XBRL document itself.
+// Unmarshal the root <link:linkbase> element of the linkbase into this struct, and use Networks() to get its calculations.
+//
+// For example:
+//
+//	<link:calculationLink xlink:type="extended" xlink:role="http://www.apple.com/role/CONDENSEDCONSOLIDATEDBALANCESHEETS">
+//	    <link:loc xlink:type="locator" xlink:href="https://xbrl.fasb.org/us-gaap/2020/elts/us-gaap-2020-01-31.xsd#us-gaap_Assets" xlink:label="loc_Assets"/>
+//	    <link:loc xlink:type="locator" xlink:href="https://xbrl.fasb.org/us-gaap/2020/elts/us-gaap-2020-01-31.xsd#us-gaap_AssetsCurrent" xlink:label="loc_AssetsCurrent"/>
+//	    <link:calculationArc xlink:type="arc" xlink:arcrole="http://www.xbrl.org/2003/arcrole/summation-item" xlink:from="loc_Assets" xlink:to="loc_AssetsCurrent" order="1" weight="1.0"/>
+//	</link:calculationLink>
+//
+// https://www.xbrl.org/Specification/XBRL-2.1/REC-2003-12-31/XBRL-2.1-REC-2003-12-31+corrected-errata-2013-02-20.html#_5.2.5
+type CalculationLinkbase struct {
+	Links []CalculationLink `xml:"calculationLink"`
+}
+
+// CalculationLink is an extended link whose calculationArcs connect the locators of totals to the locators of the items they sum.
+type CalculationLink struct {
+	// Role is the xlink:role of the extended link. Links with the same role make up a single network.
+	Role string `xml:"role,attr"`
+
+	// Locators point to concepts in a taxonomy schema, ie "us-gaap-2020-01-31.xsd#us-gaap_Assets". See Locator.FactID() for their fragment.
+	Locators []Locator        `xml:"loc"`
+	Arcs     []CalculationArc `xml:"calculationArc"`
+}
+
+// CalculationArc connects the locator of a total (From) to the locator of one of its items (To).
+// https://www.xbrl.org/Specification/XBRL-2.1/REC-2003-12-31/XBRL-2.1-REC-2003-12-31+corrected-errata-2013-02-20.html#_5.2.5.1
+type CalculationArc struct {
+	From    string `xml:"from,attr"`
+	To      string `xml:"to,attr"`
+	ArcRole string `xml:"arcrole,attr"`
+	Order   string `xml:"order,attr"`
+
+	// Weight is what the item is multiplied by before it's summed, usually 1 or -1.
+	Weight string `xml:"weight,attr"`
+
+	// Use and Priority decide which arcs override or prohibit the others that connect the same concepts.
+	// https://www.xbrl.org/Specification/XBRL-2.1/REC-2003-12-31/XBRL-2.1-REC-2003-12-31+corrected-errata-2013-02-20.html#_3.5.3.9.7.4
+	Use      string `xml:"use,attr"`
+	Priority string `xml:"priority,attr"`
+}
+
+// CalculationNetwork contains the summations of all the calculation links with the same role.
+type CalculationNetwork struct {
+	Role       string
+	Summations []Summation
+}
+
+// Summation says that the value of the Total concept is the sum of the values of its Items, each multiplied by its weight.
+type Summation struct {
+	Total QName
+	Items []SummationItem
+
+	// ArcRole is the arcrole of the arcs the summation was made from, ArcRoleSummationItem or ArcRoleSummationItem11.
+	// XBRL.CheckCalculations() only checks the summations of the arcrole that belongs to its mode, and the ones without an arcrole.
+	ArcRole string
+}
+
+// SummationItem is one of the concepts that a Summation adds up.
+type SummationItem struct {
+	Concept QName
+	Weight  *big.Rat
+}
+
+// ConceptFromHref returns the concept that a locator's href points to, following the convention that the ID of a concept's element
+// in its schema is its prefix and its name separated by an underscore, ie "us-gaap-2020-01-31.xsd#us-gaap_Assets" is us-gaap:Assets.
+//
+// The namespace of the concept isn't known without reading the schema, so Space is left empty,
+// and facts are matched to the concept by their prefix instead. See CalculationLinkbase.Networks().
+func ConceptFromHref(href string) (QName, bool) {
+	fragment := Locator{Href: href}.FactID()
+	index := strings.IndexRune(fragment, '_')
+	if index <= 0 || index == len(fragment)-1 {
+		return QName{}, false
+	}
+
+	return QName{Prefix: fragment[:index], Local: fragment[index+1:]}, true
+}
+
+// Networks returns the calculation networks of the linkbase, one for each role in the order they first appear.
+//
+// The concepts that locators point to are found with resolve, which is ConceptFromHref() if it's nil.
+// Pass a function that looks the hrefs up in the taxonomy schemas to get the namespaces of the concepts too.
+//
+// Summations are in the order of their first arc, and their items are sorted by the order attribute of their arcs.
+// The arcs of the XBRL 2.1 and the Calculations 1.1 arcroles make separate summations, even if they have the same total.
+// If several equivalent arcs, with the same weight and order, connect the same two concepts in a network,
+// the one with the highest priority is used, and they're all left out if it's use="prohibited".
+func (l CalculationLinkbase) Networks(resolve func(href string) (QName, bool)) ([]CalculationNetwork, error) {
+	if resolve == nil {
+		resolve = ConceptFromHref
+	}
+
+	type relationship struct {
+		from, to   QName
+		arcRole    string
+		weight     *big.Rat
+		order      float64
+		priority   int
+		prohibited bool
+	}
+
+	var roles []string
+	relationships := make(map[string][]relationship)
+	for _, link := range l.Links {
+		if _, exists := relationships[link.Role]; !exists {
+			roles = append(roles, link.Role)
+			relationships[link.Role] = nil
+		}
+
+		conceptsByLabel := make(map[string][]QName, len(link.Locators))
+		for _, locator := range link.Locators {
+			concept, ok := resolve(locator.Href)
+			if !ok {
+				return nil, fmt.Errorf("calculation link %s: can't find the concept of locator %s: %s", link.Role, locator.Label, locator.Href)
+			}
+
+			conceptsByLabel[locator.Label] = append(conceptsByLabel[locator.Label], concept)
+		}
+
+		for _, arc := range link.Arcs {
+			if arc.ArcRole != ArcRoleSummationItem && arc.ArcRole != ArcRoleSummationItem11 {
+				continue
+			}
+
+			weight, err := ParseXSDDecimal(arc.Weight)
+			if err != nil {
+				return nil, fmt.Errorf("calculation link %s: arc from %s to %s: weight: %w", link.Role, arc.From, arc.To, err)
+			}
+
+			// The order and priority attributes are optional, and default to 1 and 0.
+			order, priority := 1.0, 0
+			if strings.TrimSpace(arc.Order) != "" {
+				if order, err = strconv.ParseFloat(strings.TrimSpace(arc.Order), 64); err != nil {
+					return nil, fmt.Errorf("calculation link %s: arc from %s to %s: invalid order: %q", link.Role, arc.From, arc.To, arc.Order)
+				}
+			}
+
+			if strings.TrimSpace(arc.Priority) != "" {
+				if priority, err = strconv.Atoi(strings.TrimSpace(arc.Priority)); err != nil {
+					return nil, fmt.Errorf("calculation link %s: arc from %s to %s: invalid priority: %q", link.Role, arc.From, arc.To, arc.Priority)
+				}
+			}
+
+			for _, from := range conceptsByLabel[arc.From] {
+				for _, to := range conceptsByLabel[arc.To] {
+					relationships[link.Role] = append(relationships[link.Role], relationship{
+						from:       from,
+						to:         to,
+						arcRole:    arc.ArcRole,
+						weight:     weight,
+						order:      order,
+						priority:   priority,
+						prohibited: strings.TrimSpace(arc.Use) == "prohibited",
+					})
+				}
+			}
+		}
+	}
+
+	networks := make([]CalculationNetwork, 0, len(roles))
+	for _, role := range roles {
+		// Keep the relationship with the highest priority of each set of equivalent relationships. A prohibiting arc wins a tie.
+		// Relationships are equivalent if they have the same concepts, arc role and non-exempt attributes, which are the weight
+		// and order of a calculation arc, so arcs between the same concepts with different weights are all kept.
+		// https://www.xbrl.org/Specification/XBRL-2.1/REC-2003-12-31/XBRL-2.1-REC-2003-12-31+corrected-errata-2013-02-20.html#_3.5.3.9.7.4
+		type pair struct {
+			from, to, arcRole, weight string
+			order                     float64
+		}
+
+		var pairs []pair
+		winners := make(map[pair]relationship)
+		for _, relationship := range relationships[role] {
+			key := pair{qnameKey(relationship.from), qnameKey(relationship.to), relationship.arcRole, relationship.weight.RatString(), relationship.order}
+			winner, exists := winners[key]
+			switch {
+			case !exists:
+				pairs = append(pairs, key)
+			case relationship.priority < winner.priority || (relationship.priority == winner.priority && winner.prohibited):
+				continue
+			}
+
+			winners[key] = relationship
+		}
+
+		network := CalculationNetwork{Role: role}
+		summationIndexes := make(map[string]int)
+		var orders [][]float64
+		for _, key := range pairs {
+			relationship := winners[key]
+			if relationship.prohibited {
+				continue
+			}
+
+			index, exists := summationIndexes[key.from+" "+key.arcRole]
+			if !exists {
+				index = len(network.Summations)
+				summationIndexes[key.from+" "+key.arcRole] = index
+				network.Summations = append(network.Summations, Summation{Total: relationship.from, ArcRole: relationship.arcRole})
+				orders = append(orders, nil)
+			}
+
+			network.Summations[index].Items = append(network.Summations[index].Items, SummationItem{Concept: relationship.to, Weight: relationship.weight})
+			orders[index] = append(orders[index], relationship.order)
+		}
+
+		for index := range network.Summations {
+			items, itemOrders := network.Summations[index].Items, orders[index]
+			sort.Stable(summationItemsByOrder{items, itemOrders})
+		}
+
+		networks = append(networks, network)
+	}
+
+	return networks, nil
+}
+
+// summationItemsByOrder sorts the items of a summation by the order attribute of their arcs.
+type summationItemsByOrder struct {
+	items  []SummationItem
+	orders []float64
+}
+
+func (s summationItemsByOrder) Len() int           { return len(s.items) }
+func (s summationItemsByOrder) Less(i, j int) bool { return s.orders[i] < s.orders[j] }
+func (s summationItemsByOrder) Swap(i, j int) {
+	s.items[i], s.items[j] = s.items[j], s.items[i]
+	s.orders[i], s.orders[j] = s.orders[j], s.orders[i]
+}
+
+type CalculationMode string
+
+// All the CalculationMode values, which decide how the rounding of reported values is taken into account.
+const (
+	// CalculationModeXBRL21 follows section 5.2.5.2 of XBRL 2.1: every item is rounded to its own decimals before it's summed,
+	// and the sum is rounded to the decimals of the total, which must then be equal to the total rounded to its decimals.
+	// Facts that have duplicates aren't checked at all. It checks the summations of ArcRoleSummationItem.
+	// https://www.xbrl.org/Specification/XBRL-2.1/REC-2003-12-31/XBRL-2.1-REC-2003-12-31+corrected-errata-2013-02-20.html#_5.2.5.2
+	CalculationModeXBRL21 CalculationMode = "xbrl21"
+
+	// CalculationModeRound follows the round-to-nearest mode of Calculations 1.1:
+	// a value reported with decimals d is anywhere within half of 10^-d of the actual value,
+	// and the total is consistent if the range of the sum of its items overlaps with its own range.
+	// Consistent duplicates are checked using the most accurate of them. It checks the summations of ArcRoleSummationItem11.
+	// https://www.xbrl.org/Specification/calculation-1.1/REC-2023-02-22/calculation-1.1-REC-2023-02-22.html
+	CalculationModeRound CalculationMode = "round"
+
+	// CalculationModeTruncate follows the truncation mode of Calculations 1.1, where values are truncated towards zero
+	// rather than rounded: a value v reported with decimals d is in [v, v+10^-d) if it's positive, (v-10^-d, v] if it's negative,
+	// and (-10^-d, 10^-d) if it's zero. The total is consistent if the range of the sum of its items overlaps with its own range.
+	// Like CalculationModeRound, it checks the summations of ArcRoleSummationItem11.
+	CalculationModeTruncate CalculationMode = "truncate"
+)
+
+// arcRole returns the summation-item arcrole of the summations that are checked in the mode.
+func (m CalculationMode) arcRole() string {
+	if m == CalculationModeXBRL21 {
+		return ArcRoleSummationItem
+	}
+
+	return ArcRoleSummationItem11
+}
+
+// CalculationInconsistency is a total that isn't equal to the sum of its items, as reported by XBRL.CheckCalculations().
+type CalculationInconsistency struct {
+	// Role is the role of the CalculationNetwork of the summation.
+	Role      string
+	Summation Summation
+
+	// Total is the fact of the summation's total, and Items are the facts of its items that were summed, in the order of Summation.Items.
+	// Items that weren't reported are left out. They point to facts in XBRL.Facts.
+	Total *Fact
+	Items []*Fact
+
+	// Expected is the sum of the items multiplied by their weights, and Actual is the value of the total.
+	// In CalculationModeXBRL21 they're both rounded to Decimals.
+	Expected *big.Rat
+	Actual   *big.Rat
+
+	// Tolerance is how far apart Expected and Actual could be, in the direction they're apart, for their ranges to overlap.
+	// If Expected is below Actual, it's how far the top of ExpectedRange is above Expected plus how far the bottom of ActualRange
+	// is below Actual, and the other way around if Expected is above Actual. So the summation would be consistent if the difference
+	// was less than the Tolerance, or equal to it if neither of those bounds is open.
+	// In CalculationModeRound that's half the sum of the widths of the ranges, but the ranges of CalculationModeTruncate are one-sided.
+	// It's always zero in CalculationModeXBRL21, where the rounding is applied to the values instead.
+	Tolerance *big.Rat
+
+	// ExpectedRange is the range of the weighted sum of the items, and ActualRange the range of the total.
+	// The summation is inconsistent because they don't overlap. They're nil in CalculationModeXBRL21.
+	// Decimals so large or so small that they can't change the outcome are clamped first (see clampDecimals()),
+	// so the ranges and the Tolerance of facts with such decimals are only as wide or as narrow as needed to show that.
+	ExpectedRange *Interval
+	ActualRange   *Interval
+
+	// Decimals is the decimals of the total in CalculationModeXBRL21, and the lowest decimals of all the facts in the other modes.
+	Decimals Accuracy
+}
+
+// String describes the inconsistency, ie "us-gaap:Assets (id f1) is 100 but its items sum to 90 (tolerance 0, decimals 0)".
+func (c CalculationInconsistency) String() string {
+	return fmt.Sprintf("%s is %s but its items sum to %s (tolerance %s, decimals %s)",
+		c.Total.describe(), formatRat(c.Actual), formatRat(c.Expected), formatRat(c.Tolerance), c.Decimals)
+}
+
+// formatRat returns the exact decimal representation of value if it has one, or its fraction otherwise.
+// A fraction has a decimal representation if its denominator only has the factors 2 and 5, and it needs as many digits as the most of them.
+func formatRat(value *big.Rat) string {
+	denominator := new(big.Int).Set(value.Denom())
+	digits := 0
+	for _, factor := range []int64{2, 5} {
+		count := 0
+		divisor := big.NewInt(factor)
+		for remainder := new(big.Int); ; count++ {
+			quotient, _ := new(big.Int).QuoRem(denominator, divisor, remainder)
+			if remainder.Sign() != 0 {
+				break
+			}
+
+			denominator = quotient
+		}
+
+		if count > digits {
+			digits = count
+		}
+	}
+
+	if denominator.Cmp(big.NewInt(1)) != 0 {
+		return value.RatString()
+	}
+
+	return value.FloatString(digits)
+}
+
+// CheckCalculations checks that the totals of the summations in networks are equal to the sum of their items,
+// taking the rounding of the values into account as the mode says, and returns the ones that aren't.
+//
+// A summation is checked for every numeric fact of its total concept that has at least one of its items reported
+// in an equal context (see Context.Equal()) and unit (see Unit.Equal()), under the same parent tuple.
+// Items that aren't reported count as zero, and so do nil facts. Facts whose value or decimals can't be parsed,
+// and facts with inconsistent duplicates (see DuplicateFacts()) can't be checked, so the summations they're part of are skipped.
+//
+// Only the summations of the arcrole that belongs to the mode are checked, see CalculationMode.
+// Concepts whose namespace isn't known (ie from ConceptFromHref()) are matched to facts by prefix instead.
+func (x XBRL) CheckCalculations(networks []CalculationNetwork, mode CalculationMode) []CalculationInconsistency {
+	type bindingKey struct {
+		concept, context, unit string
+		tuple                  *Tuple
+	}
+
+	keys := x.referenceKeys()
+	bindings := make(map[bindingKey][]*Fact)
+
+	// bindingsByConcept are the keys of bindings for each concept, in the order of their first fact.
+	bindingsByConcept := make(map[string][]bindingKey)
+	add := func(key bindingKey, fact *Fact) {
+		if len(bindings[key]) == 0 {
+			bindingsByConcept[key.concept] = append(bindingsByConcept[key.concept], key)
+		}
+
+		bindings[key] = append(bindings[key], fact)
+	}
+
+	for index := range x.Facts {
+		fact := &x.Facts[index]
+		if fact.UnitRef == nil || fact.Type() == FactTypeNil || !fact.IsValid() {
+			continue
+		}
+
+		key := bindingKey{tuple: fact.Tuple}
+		key.context, key.unit = keys.of(*fact)
+
+		concept := factConcept(*fact)
+		key.concept = qnameKey(concept)
+		add(key, fact)
+
+		// Index the fact by its prefix too, for concepts whose namespace isn't known.
+		if concept.Space != "" {
+			key.concept = qnameKey(QName{Prefix: concept.Prefix, Local: concept.Local})
+			add(key, fact)
+		}
+	}
+
+	var inconsistencies []CalculationInconsistency
+	for _, network := range networks {
+		for _, summation := range network.Summations {
+			if summation.ArcRole != "" && summation.ArcRole != mode.arcRole() {
+				continue
+			}
+
+			for _, key := range bindingsByConcept[qnameKey(summation.Total)] {
+				total, ok := calculationFact(bindings[key], mode)
+				if !ok {
+					continue
+				}
+
+				items := make([]*Fact, 0, len(summation.Items))
+				weights := make([]*big.Rat, 0, len(summation.Items))
+				bindable := true
+				for _, item := range summation.Items {
+					itemKey := key
+					itemKey.concept = qnameKey(item.Concept)
+					facts := bindings[itemKey]
+					if len(facts) == 0 {
+						continue
+					}
+
+					fact, ok := calculationFact(facts, mode)
+					if !ok {
+						bindable = false
+						break
+					}
+
+					items = append(items, fact)
+					weights = append(weights, item.Weight)
+				}
+
+				if !bindable || len(items) == 0 {
+					continue
+				}
+
+				inconsistency, consistent := checkSummation(total, items, weights, mode)
+				if !consistent {
+					inconsistency.Role = network.Role
+					inconsistency.Summation = summation
+					inconsistencies = append(inconsistencies, inconsistency)
+				}
+			}
+		}
+	}
+
+	return inconsistencies
+}
+
+// calculationFact returns the fact to use in a calculation out of a set of duplicate facts, or false if they can't be used.
+// XBRL 2.1 doesn't use facts that have duplicates, and Calculations 1.1 uses the most accurate fact of consistent duplicates.
+func calculationFact(facts []*Fact, mode CalculationMode) (*Fact, bool) {
+	for _, fact := range facts {
+		if _, err := fact.RatValue(); err != nil {
+			return nil, false
+		}
+
+		if _, err := fact.DecimalsValue(); err != nil {
+			return nil, false
+		}
+	}
+
+	switch {
+	case len(facts) == 1:
+		return facts[0], true
+	case mode == CalculationModeXBRL21 || classifyDuplicates(facts) == DuplicateClassInconsistent:
+		return nil, false
+	}
+
+	best := facts[0]
+	bestDecimals, _ := best.DecimalsValue()
+	for _, fact := range facts[1:] {
+		decimals, _ := fact.DecimalsValue()
+		if !bestDecimals.Infinite && (decimals.Infinite || decimals.Value > bestDecimals.Value) {
+			best, bestDecimals = fact, decimals
+		}
+	}
+
+	return best, true
+}
+
+// checkSummation compares the value of total with the weighted sum of items. The values and decimals of all the facts must be valid.
+// It returns the inconsistency without its Role and Summation, and whether the summation is consistent.
+func checkSummation(total *Fact, items []*Fact, weights []*big.Rat, mode CalculationMode) (CalculationInconsistency, bool) {
+	inconsistency := CalculationInconsistency{Total: total, Items: items, Expected: new(big.Rat), Tolerance: new(big.Rat)}
+	inconsistency.Actual, _ = total.RatValue()
+	totalDecimals, _ := total.DecimalsValue()
+
+	if mode == CalculationModeXBRL21 {
+		for index, item := range items {
+			rounded, _ := item.RoundedValue()
+			inconsistency.Expected.Add(inconsistency.Expected, rounded.Mul(rounded, weights[index]))
+		}
+
+		inconsistency.Decimals = totalDecimals
+		inconsistency.Expected = RoundRat(inconsistency.Expected, totalDecimals)
+		inconsistency.Actual = RoundRat(inconsistency.Actual, totalDecimals)
+		return inconsistency, inconsistency.Expected.Cmp(inconsistency.Actual) == 0
+	}
+
+	// Decimals can be any integer, so they're clamped to what can make a difference to the values of this summation.
+	limit := 2 + ratDigits(inconsistency.Actual)
+	for index, item := range items {
+		value, _ := item.RatValue()
+		limit += ratDigits(value) + ratDigits(weights[index])
+	}
+
+	inconsistency.Decimals = totalDecimals
+	inconsistency.ActualRange = valueInterval(inconsistency.Actual, clampDecimals(totalDecimals, limit), mode)
+	inconsistency.ExpectedRange = &Interval{Low: new(big.Rat), High: new(big.Rat)}
+	for index, item := range items {
+		value, _ := item.RatValue()
+		decimals, _ := item.DecimalsValue()
+		interval := valueInterval(value, clampDecimals(decimals, limit), mode).scale(weights[index])
+
+		inconsistency.Expected.Add(inconsistency.Expected, value.Mul(value, weights[index]))
+		inconsistency.ExpectedRange.add(interval)
+		if !decimals.Infinite && (inconsistency.Decimals.Infinite || decimals.Value < inconsistency.Decimals.Value) {
+			inconsistency.Decimals = decimals
+		}
+	}
+
+	// The ranges can only meet on the side of Expected that Actual is on.
+	expected, actual := inconsistency.ExpectedRange, inconsistency.ActualRange
+	if inconsistency.Expected.Cmp(inconsistency.Actual) < 0 {
+		inconsistency.Tolerance.Sub(expected.High, inconsistency.Expected)
+		inconsistency.Tolerance.Add(inconsistency.Tolerance, new(big.Rat).Sub(inconsistency.Actual, actual.Low))
+	} else {
+		inconsistency.Tolerance.Sub(inconsistency.Expected, expected.Low)
+		inconsistency.Tolerance.Add(inconsistency.Tolerance, new(big.Rat).Sub(actual.High, inconsistency.Actual))
+	}
+
+	return inconsistency, expected.Overlaps(*actual)
+}
+
+// ratDigits returns the number of digits in the numerator and denominator of value.
+func ratDigits(value *big.Rat) int {
+	return len(value.Num().String()) + len(value.Denom().String())
+}
+
+// clampDecimals keeps decimals within a few times limit, the number of digits of all the values and weights of a summation.
+// Beyond that, 10^-decimals is either so big that a range is wider than the distance between any values and ranges of the summation,
+// or so small that the sum of all such ranges is narrower than the smallest difference between them, whatever decimals actually is.
+// Either way comparing the ranges gives the same result, without computing powers of ten with millions of digits.
+func clampDecimals(decimals Accuracy, limit int) Accuracy {
+	switch {
+	case decimals.Infinite:
+		return decimals
+	case decimals.Value < -limit:
+		return Accuracy{Value: -3 * limit}
+	case decimals.Value > 2*limit:
+		return Accuracy{Value: 4 * limit}
+	default:
+		return decimals
+	}
+}
+
+// Interval is a range of values that an accurate value can be in. Each bound is either included (closed) or excluded (open).
+type Interval struct {
+	Low, High         *big.Rat
+	LowOpen, HighOpen bool
+}
+
+// valueInterval returns the range of values that value reported with decimals could have been rounded or truncated from.
+func valueInterval(value *big.Rat, decimals Accuracy, mode CalculationMode) *Interval {
+	interval := &Interval{Low: new(big.Rat).Set(value), High: new(big.Rat).Set(value)}
+	if decimals.Infinite {
+		return interval
+	}
+
+	unit := powerOfTen(-decimals.Value)
+	if mode != CalculationModeTruncate {
+		half := new(big.Rat).Mul(unit, big.NewRat(1, 2))
+		interval.Low.Sub(interval.Low, half)
+		interval.High.Add(interval.High, half)
+		return interval
+	}
+
+	// Truncating moves values towards zero, so the accurate value is further from zero than the reported one.
+	if value.Sign() >= 0 {
+		interval.High.Add(interval.High, unit)
+		interval.HighOpen = true
+	}
+
+	if value.Sign() <= 0 {
+		interval.Low.Sub(interval.Low, unit)
+		interval.LowOpen = true
+	}
+
+	return interval
+}
+
+// scale returns the interval multiplied by weight. A negative weight swaps the bounds.
+func (i Interval) scale(weight *big.Rat) *Interval {
+	scaled := &Interval{Low: new(big.Rat).Mul(i.Low, weight), High: new(big.Rat).Mul(i.High, weight), LowOpen: i.LowOpen, HighOpen: i.HighOpen}
+	if weight.Sign() < 0 {
+		scaled.Low, scaled.High = scaled.High, scaled.Low
+		scaled.LowOpen, scaled.HighOpen = scaled.HighOpen, scaled.LowOpen
+	}
+
+	return scaled
+}
+
+// add adds other to the interval. A bound of the sum is open if either of the bounds that were added is open.
+func (i *Interval) add(other *Interval) {
+	i.Low.Add(i.Low, other.Low)
+	i.High.Add(i.High, other.High)
+	i.LowOpen = i.LowOpen || other.LowOpen
+	i.HighOpen = i.HighOpen || other.HighOpen
+}
+
+// Overlaps returns true if there's at least one value that's in both intervals.
+func (i Interval) Overlaps(other Interval) bool {
+	below := func(low *big.Rat, lowOpen bool, high *big.Rat, highOpen bool) bool {
+		comparison := low.Cmp(high)
+		return comparison < 0 || (comparison == 0 && !lowOpen && !highOpen)
+	}
+
+	return below(i.Low, i.LowOpen, other.High, other.HighOpen) && below(other.Low, other.LowOpen, i.High, i.HighOpen)
+}
+
+// String returns the interval in the usual notation, ie "[100, 102)".
+func (i Interval) String() string {
+	open, close := "[", "]"
+	if i.LowOpen {
+		open = "("
+	}
+
+	if i.HighOpen {
+		close = ")"
+	}
+
+	return open + formatRat(i.Low) + ", " + formatRat(i.High) + close
+}
@@ -0,0 +1,468 @@
+package xbrl
+
+import (
+	"encoding/xml"
+	"math/big"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestConceptFromHref(t *testing.T) {
+	testCases := []struct {
+		href     string
+		expected QName
+		ok       bool
+	}{
+		{"https://xbrl.fasb.org/us-gaap/2020/elts/us-gaap-2020-01-31.xsd#us-gaap_Assets", QName{Prefix: "us-gaap", Local: "Assets"}, true},
+		{"aapl-20210327.xsd#aapl_NonTradeReceivables", QName{Prefix: "aapl", Local: "NonTradeReceivables"}, true},
+		{"#ci_Revenue_Net", QName{Prefix: "ci", Local: "Revenue_Net"}, true},
+		{"us-gaap-2020-01-31.xsd#Assets", QName{}, false},
+		{"us-gaap-2020-01-31.xsd", QName{}, false},
+		{"#_Assets", QName{}, false},
+		{"#us-gaap_", QName{}, false},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.href, func(t *testing.T) {
+			concept, ok := ConceptFromHref(testCase.href)
+			assert.Equal(t, testCase.ok, ok)
+			assert.Equal(t, testCase.expected, concept)
+		})
+	}
+}
+
+func TestCalculationNetworks(t *testing.T) {
+	// language=xml
+	const linkbase = `<link:linkbase xmlns:link="http://www.xbrl.org/2003/linkbase" xmlns:xlink="http://www.w3.org/1999/xlink">
+    <link:calculationLink xlink:type="extended" xlink:role="http://example.com/role/BalanceSheet">
+        <link:loc xlink:type="locator" xlink:href="ci.xsd#ci_Assets" xlink:label="Assets"/>
+        <link:loc xlink:type="locator" xlink:href="ci.xsd#ci_AssetsNoncurrent" xlink:label="AssetsNoncurrent"/>
+        <link:loc xlink:type="locator" xlink:href="ci.xsd#ci_AssetsCurrent" xlink:label="AssetsCurrent"/>
+        <link:calculationArc xlink:type="arc" xlink:arcrole="http://www.xbrl.org/2003/arcrole/summation-item" xlink:from="Assets" xlink:to="AssetsNoncurrent" order="2" weight="1.0"/>
+        <link:calculationArc xlink:type="arc" xlink:arcrole="http://www.xbrl.org/2003/arcrole/summation-item" xlink:from="Assets" xlink:to="AssetsCurrent" order="1" weight="1.0"/>
+    </link:calculationLink>
+    <link:calculationLink xlink:type="extended" xlink:role="http://example.com/role/IncomeStatement">
+        <link:loc xlink:type="locator" xlink:href="ci.xsd#ci_GrossProfit" xlink:label="GrossProfit"/>
+        <link:loc xlink:type="locator" xlink:href="ci.xsd#ci_Revenues" xlink:label="Revenues"/>
+        <link:loc xlink:type="locator" xlink:href="ci.xsd#ci_CostOfRevenue" xlink:label="CostOfRevenue"/>
+        <link:calculationArc xlink:type="arc" xlink:arcrole="https://xbrl.org/2023/arcrole/summation-item" xlink:from="GrossProfit" xlink:to="Revenues" weight="1"/>
+        <link:calculationArc xlink:type="arc" xlink:arcrole="https://xbrl.org/2023/arcrole/summation-item" xlink:from="GrossProfit" xlink:to="CostOfRevenue" weight="-1"/>
+        <link:calculationArc xlink:type="arc" xlink:arcrole="http://example.com/arcrole/other" xlink:from="Revenues" xlink:to="CostOfRevenue" weight="1"/>
+    </link:calculationLink>
+    <link:calculationLink xlink:type="extended" xlink:role="http://example.com/role/BalanceSheet">
+        <link:loc xlink:type="locator" xlink:href="ci.xsd#ci_Assets" xlink:label="Assets"/>
+        <link:loc xlink:type="locator" xlink:href="ci.xsd#ci_AssetsNoncurrent" xlink:label="AssetsNoncurrent"/>
+        <link:loc xlink:type="locator" xlink:href="ci.xsd#ci_Equity" xlink:label="Equity"/>
+        <link:loc xlink:type="locator" xlink:href="ci.xsd#ci_Liabilities" xlink:label="Liabilities"/>
+        <link:calculationArc xlink:type="arc" xlink:arcrole="http://www.xbrl.org/2003/arcrole/summation-item" xlink:from="Assets" xlink:to="AssetsNoncurrent" order="2" weight="1.0" use="prohibited" priority="1"/>
+        <link:calculationArc xlink:type="arc" xlink:arcrole="http://www.xbrl.org/2003/arcrole/summation-item" xlink:from="Equity" xlink:to="Liabilities" weight="-1.0"/>
+    </link:calculationLink>
+</link:linkbase>`
+
+	var calculations CalculationLinkbase
+	require.NoError(t, xml.Unmarshal([]byte(linkbase), &calculations))
+	require.Len(t, calculations.Links, 3)
+
+	t.Run("networks", func(t *testing.T) {
+		networks, err := calculations.Networks(nil)
+		require.NoError(t, err)
+		require.Len(t, networks, 2)
+
+		balanceSheet := networks[0]
+		assert.Equal(t, "http://example.com/role/BalanceSheet", balanceSheet.Role)
+		assert.Equal(t, []Summation{
+			// The arc to AssetsNoncurrent is prohibited by the third link.
+			{Total: QName{Prefix: "ci", Local: "Assets"}, Items: []SummationItem{{Concept: QName{Prefix: "ci", Local: "AssetsCurrent"}, Weight: big.NewRat(1, 1)}}, ArcRole: ArcRoleSummationItem},
+			{Total: QName{Prefix: "ci", Local: "Equity"}, Items: []SummationItem{{Concept: QName{Prefix: "ci", Local: "Liabilities"}, Weight: big.NewRat(-1, 1)}}, ArcRole: ArcRoleSummationItem},
+		}, balanceSheet.Summations)
+
+		incomeStatement := networks[1]
+		assert.Equal(t, "http://example.com/role/IncomeStatement", incomeStatement.Role)
+		require.Len(t, incomeStatement.Summations, 1)
+		assert.Equal(t, ArcRoleSummationItem11, incomeStatement.Summations[0].ArcRole)
+		assert.Equal(t, []SummationItem{
+			{Concept: QName{Prefix: "ci", Local: "Revenues"}, Weight: big.NewRat(1, 1)},
+			{Concept: QName{Prefix: "ci", Local: "CostOfRevenue"}, Weight: big.NewRat(-1, 1)},
+		}, incomeStatement.Summations[0].Items)
+	})
+
+	t.Run("items are sorted by order", func(t *testing.T) {
+		networks, err := (CalculationLinkbase{Links: calculations.Links[:1]}).Networks(nil)
+		require.NoError(t, err)
+		require.Len(t, networks, 1)
+		require.Len(t, networks[0].Summations, 1)
+
+		items := networks[0].Summations[0].Items
+		require.Len(t, items, 2)
+		assert.Equal(t, "AssetsCurrent", items[0].Concept.Local)
+		assert.Equal(t, "AssetsNoncurrent", items[1].Concept.Local)
+	})
+
+	t.Run("arcroles make separate summations", func(t *testing.T) {
+		// language=xml
+		const linkbase = `<link:linkbase xmlns:link="http://www.xbrl.org/2003/linkbase" xmlns:xlink="http://www.w3.org/1999/xlink">
+    <link:calculationLink xlink:type="extended" xlink:role="http://example.com/role/BalanceSheet">
+        <link:loc xlink:type="locator" xlink:href="ci.xsd#ci_Assets" xlink:label="Assets"/>
+        <link:loc xlink:type="locator" xlink:href="ci.xsd#ci_AssetsCurrent" xlink:label="AssetsCurrent"/>
+        <link:loc xlink:type="locator" xlink:href="ci.xsd#ci_AssetsNoncurrent" xlink:label="AssetsNoncurrent"/>
+        <link:calculationArc xlink:type="arc" xlink:arcrole="http://www.xbrl.org/2003/arcrole/summation-item" xlink:from="Assets" xlink:to="AssetsCurrent" weight="1"/>
+        <link:calculationArc xlink:type="arc" xlink:arcrole="https://xbrl.org/2023/arcrole/summation-item" xlink:from="Assets" xlink:to="AssetsNoncurrent" weight="1"/>
+    </link:calculationLink>
+</link:linkbase>`
+
+		var calculations CalculationLinkbase
+		require.NoError(t, xml.Unmarshal([]byte(linkbase), &calculations))
+
+		networks, err := calculations.Networks(nil)
+		require.NoError(t, err)
+		require.Len(t, networks, 1)
+		require.Len(t, networks[0].Summations, 2)
+
+		assert.Equal(t, ArcRoleSummationItem, networks[0].Summations[0].ArcRole)
+		assert.Equal(t, []SummationItem{{Concept: QName{Prefix: "ci", Local: "AssetsCurrent"}, Weight: big.NewRat(1, 1)}}, networks[0].Summations[0].Items)
+		assert.Equal(t, ArcRoleSummationItem11, networks[0].Summations[1].ArcRole)
+		assert.Equal(t, []SummationItem{{Concept: QName{Prefix: "ci", Local: "AssetsNoncurrent"}, Weight: big.NewRat(1, 1)}}, networks[0].Summations[1].Items)
+	})
+
+	t.Run("equivalent relationships", func(t *testing.T) {
+		// language=xml
+		const linkbase = `<link:linkbase xmlns:link="http://www.xbrl.org/2003/linkbase" xmlns:xlink="http://www.w3.org/1999/xlink">
+    <link:calculationLink xlink:type="extended" xlink:role="http://example.com/role/BalanceSheet">
+        <link:loc xlink:type="locator" xlink:href="ci.xsd#ci_Assets" xlink:label="Assets"/>
+        <link:loc xlink:type="locator" xlink:href="ci.xsd#ci_AssetsCurrent" xlink:label="AssetsCurrent"/>
+        <link:loc xlink:type="locator" xlink:href="ci.xsd#ci_AssetsNoncurrent" xlink:label="AssetsNoncurrent"/>
+        <link:calculationArc xlink:type="arc" xlink:arcrole="http://www.xbrl.org/2003/arcrole/summation-item" xlink:from="Assets" xlink:to="AssetsCurrent" weight="1"/>
+        <link:calculationArc xlink:type="arc" xlink:arcrole="http://www.xbrl.org/2003/arcrole/summation-item" xlink:from="Assets" xlink:to="AssetsNoncurrent" weight="1"/>
+    </link:calculationLink>
+    <link:calculationLink xlink:type="extended" xlink:role="http://example.com/role/BalanceSheet">
+        <link:loc xlink:type="locator" xlink:href="ci.xsd#ci_Assets" xlink:label="Assets"/>
+        <link:loc xlink:type="locator" xlink:href="ci.xsd#ci_AssetsCurrent" xlink:label="AssetsCurrent"/>
+        <link:loc xlink:type="locator" xlink:href="ci.xsd#ci_AssetsNoncurrent" xlink:label="AssetsNoncurrent"/>
+        <link:calculationArc xlink:type="arc" xlink:arcrole="http://www.xbrl.org/2003/arcrole/summation-item" xlink:from="Assets" xlink:to="AssetsCurrent" weight="-1"/>
+        <link:calculationArc xlink:type="arc" xlink:arcrole="http://www.xbrl.org/2003/arcrole/summation-item" xlink:from="Assets" xlink:to="AssetsNoncurrent" weight="2" use="prohibited" priority="1"/>
+    </link:calculationLink>
+</link:linkbase>`
+
+		var calculations CalculationLinkbase
+		require.NoError(t, xml.Unmarshal([]byte(linkbase), &calculations))
+
+		networks, err := calculations.Networks(nil)
+		require.NoError(t, err)
+		require.Len(t, networks, 1)
+		require.Len(t, networks[0].Summations, 1)
+
+		// Arcs with different weights aren't equivalent, so neither overrides or prohibits the other.
+		assert.Equal(t, []SummationItem{
+			{Concept: QName{Prefix: "ci", Local: "AssetsCurrent"}, Weight: big.NewRat(1, 1)},
+			{Concept: QName{Prefix: "ci", Local: "AssetsNoncurrent"}, Weight: big.NewRat(1, 1)},
+			{Concept: QName{Prefix: "ci", Local: "AssetsCurrent"}, Weight: big.NewRat(-1, 1)},
+		}, networks[0].Summations[0].Items)
+	})
+
+	t.Run("custom resolver", func(t *testing.T) {
+		const namespace = "http://www.xbrl.org/us/gaap/ci/2003/usfr-ci-2003"
+		networks, err := calculations.Networks(func(href string) (QName, bool) {
+			concept, ok := ConceptFromHref(href)
+			concept.Space = namespace
+			return concept, ok
+		})
+
+		require.NoError(t, err)
+		assert.Equal(t, namespace, networks[0].Summations[0].Total.Space)
+	})
+
+	t.Run("unknown concept", func(t *testing.T) {
+		_, err := calculations.Networks(func(href string) (QName, bool) {
+			return QName{}, false
+		})
+
+		assert.EqualError(t, err, "calculation link http://example.com/role/BalanceSheet: can't find the concept of locator Assets: ci.xsd#ci_Assets")
+	})
+
+	t.Run("invalid weight", func(t *testing.T) {
+		invalid := CalculationLinkbase{Links: []CalculationLink{{
+			Role:     "http://example.com/role/BalanceSheet",
+			Locators: []Locator{{Href: "#ci_Assets", Label: "Assets"}, {Href: "#ci_AssetsCurrent", Label: "AssetsCurrent"}},
+			Arcs:     []CalculationArc{{From: "Assets", To: "AssetsCurrent", ArcRole: ArcRoleSummationItem, Weight: "one"}},
+		}}}
+
+		_, err := invalid.Networks(nil)
+		assert.ErrorIs(t, err, ErrInvalidLexicalValue)
+	})
+}
+
+func TestCheckCalculations(t *testing.T) {
+	// language=xml
+	const doc = `<xbrl xmlns:ci="http://www.xbrl.org/us/gaap/ci/2003/usfr-ci-2003" xmlns:iso4217="http://www.xbrl.org/2003/iso4217">
+    <context id="c1"><entity><identifier scheme="http://www.sec.gov/CIK">0000320193</identifier></entity><period><instant>2021-03-27</instant></period></context>
+    <context id="c1-copy"><entity><identifier scheme="http://www.sec.gov/CIK">0000320193</identifier></entity><period><instant>2021-03-27</instant></period></context>
+    <context id="c2"><entity><identifier scheme="http://www.sec.gov/CIK">0000320193</identifier></entity><period><instant>2020-09-26</instant></period></context>
+    <unit id="usd"><measure>iso4217:USD</measure></unit>
+
+    <ci:Assets id="assets" contextRef="c1" unitRef="usd" decimals="-6">337158000000</ci:Assets>
+    <ci:AssetsCurrent contextRef="c1" unitRef="usd" decimals="-6">121465000000</ci:AssetsCurrent>
+    <ci:AssetsNoncurrent contextRef="c1-copy" unitRef="usd" decimals="-6">215693000000</ci:AssetsNoncurrent>
+    <ci:AssetsNoncurrent contextRef="c2" unitRef="usd" decimals="-6">1</ci:AssetsNoncurrent>
+
+    <ci:Equity contextRef="c1" unitRef="usd" decimals="0">7</ci:Equity>
+    <ci:TotalAssets contextRef="c1" unitRef="usd" decimals="0">10</ci:TotalAssets>
+    <ci:Liabilities contextRef="c1" unitRef="usd" decimals="0">3</ci:Liabilities>
+    <ci:Provisions contextRef="c1" unitRef="usd" xsi:nil="true"/>
+
+    <ci:Rounded contextRef="c1" unitRef="usd" decimals="0">100</ci:Rounded>
+    <ci:RoundedA contextRef="c1" unitRef="usd" decimals="1">33.4</ci:RoundedA>
+    <ci:RoundedB contextRef="c1" unitRef="usd" decimals="1">33.4</ci:RoundedB>
+    <ci:RoundedC contextRef="c1" unitRef="usd" decimals="1">33.4</ci:RoundedC>
+
+    <ci:Truncated id="truncated" contextRef="c1" unitRef="usd" decimals="0">12</ci:Truncated>
+    <ci:TruncatedA contextRef="c1" unitRef="usd" decimals="0">5</ci:TruncatedA>
+    <ci:TruncatedB contextRef="c1" unitRef="usd" decimals="0">5</ci:TruncatedB>
+
+    <ci:Wrong id="wrong" contextRef="c1" unitRef="usd" decimals="0">100</ci:Wrong>
+    <ci:WrongA contextRef="c1" unitRef="usd" decimals="0">50</ci:WrongA>
+    <ci:WrongB contextRef="c1" unitRef="usd" decimals="0">40</ci:WrongB>
+
+    <ci:Duplicated id="duplicated" contextRef="c1" unitRef="usd" decimals="0">100</ci:Duplicated>
+    <ci:DuplicatedA contextRef="c1" unitRef="usd" decimals="-2">100</ci:DuplicatedA>
+    <ci:DuplicatedA contextRef="c1" unitRef="usd" decimals="0">60</ci:DuplicatedA>
+    <ci:DuplicatedB contextRef="c1" unitRef="usd" decimals="0">40</ci:DuplicatedB>
+
+    <ci:Inconsistent contextRef="c1" unitRef="usd" decimals="0">100</ci:Inconsistent>
+    <ci:InconsistentA contextRef="c1" unitRef="usd" decimals="0">5</ci:InconsistentA>
+    <ci:InconsistentA contextRef="c1" unitRef="usd" decimals="0">6</ci:InconsistentA>
+</xbrl>`
+
+	var content XBRL
+	require.NoError(t, xml.Unmarshal([]byte(doc), &content))
+	require.NoError(t, content.Validate())
+
+	summation := func(total string, items ...string) Summation {
+		summation := Summation{Total: QName{Prefix: "ci", Local: total}}
+		for _, item := range items {
+			weight := big.NewRat(1, 1)
+			if item[0] == '-' {
+				item, weight = item[1:], big.NewRat(-1, 1)
+			}
+
+			summation.Items = append(summation.Items, SummationItem{Concept: QName{Prefix: "ci", Local: item}, Weight: weight})
+		}
+
+		return summation
+	}
+
+	networks := []CalculationNetwork{{
+		Role: "http://example.com/role/BalanceSheet",
+		Summations: []Summation{
+			summation("Assets", "AssetsCurrent", "AssetsNoncurrent"),
+			summation("Equity", "TotalAssets", "-Liabilities", "-Provisions"),
+			summation("Rounded", "RoundedA", "RoundedB", "RoundedC"),
+			summation("Truncated", "TruncatedA", "TruncatedB"),
+			summation("Wrong", "WrongA", "WrongB", "Missing"),
+			summation("Duplicated", "DuplicatedA", "DuplicatedB"),
+			summation("Inconsistent", "InconsistentA"),
+			summation("Missing", "WrongA"),
+		},
+	}}
+
+	totals := func(inconsistencies []CalculationInconsistency) []string {
+		var ids []string
+		for _, inconsistency := range inconsistencies {
+			ids = append(ids, inconsistency.Total.ID)
+		}
+
+		return ids
+	}
+
+	t.Run("xbrl 2.1", func(t *testing.T) {
+		inconsistencies := content.CheckCalculations(networks, CalculationModeXBRL21)
+		assert.Equal(t, []string{"truncated", "wrong"}, totals(inconsistencies))
+
+		truncated := inconsistencies[0]
+		assert.Equal(t, "http://example.com/role/BalanceSheet", truncated.Role)
+		assert.Equal(t, "Truncated", truncated.Summation.Total.Local)
+		assert.Same(t, &content.Facts[12], truncated.Total)
+		assert.Equal(t, []*Fact{&content.Facts[13], &content.Facts[14]}, truncated.Items)
+		assert.Equal(t, big.NewRat(10, 1), truncated.Expected)
+		assert.Equal(t, big.NewRat(12, 1), truncated.Actual)
+		assert.Equal(t, 0, truncated.Tolerance.Sign())
+		assert.Equal(t, Accuracy{Value: 0}, truncated.Decimals)
+
+		assert.Equal(t, "ci:Wrong (id wrong) at line 26, column 5 (offset 1939) is 100 but its items sum to 90 (tolerance 0, decimals 0)",
+			inconsistencies[1].String())
+	})
+
+	t.Run("round", func(t *testing.T) {
+		inconsistencies := content.CheckCalculations(networks, CalculationModeRound)
+		// Summing the less accurate of the DuplicatedA facts would make Duplicated inconsistent, but the most accurate one is used.
+		assert.Equal(t, []string{"truncated", "wrong"}, totals(inconsistencies))
+
+		truncated := inconsistencies[0]
+		assert.Equal(t, big.NewRat(10, 1), truncated.Expected)
+		assert.Equal(t, big.NewRat(12, 1), truncated.Actual)
+		assert.Equal(t, big.NewRat(3, 2), truncated.Tolerance)
+	})
+
+	t.Run("truncate", func(t *testing.T) {
+		// 5 and 5 could have been truncated from anything in [10, 12), which doesn't reach the [12, 13) of 12.
+		inconsistencies := content.CheckCalculations(networks, CalculationModeTruncate)
+		assert.Equal(t, []string{"truncated", "wrong"}, totals(inconsistencies))
+
+		truncated := inconsistencies[0]
+		assert.Equal(t, "[10, 12)", truncated.ExpectedRange.String())
+		assert.Equal(t, "[12, 13)", truncated.ActualRange.String())
+		// The sum could be up to 2 above 10, and the total 0 below 12, but the top of the sum's range is open.
+		assert.Equal(t, big.NewRat(2, 1), truncated.Tolerance)
+
+		wrong := inconsistencies[1]
+		assert.Equal(t, big.NewRat(90, 1), wrong.Expected)
+		assert.Equal(t, big.NewRat(100, 1), wrong.Actual)
+		assert.Equal(t, big.NewRat(2, 1), wrong.Tolerance)
+		assert.Equal(t, "ci:Wrong (id wrong) at line 26, column 5 (offset 1939) is 100 but its items sum to 90 (tolerance 2, decimals 0)", wrong.String())
+	})
+
+	t.Run("arcroles", func(t *testing.T) {
+		complete, partial := summation("Assets", "AssetsCurrent", "AssetsNoncurrent"), summation("Assets", "AssetsCurrent")
+		complete.ArcRole, partial.ArcRole = ArcRoleSummationItem, ArcRoleSummationItem11
+		networks := []CalculationNetwork{{Summations: []Summation{complete, partial}}}
+
+		// Each mode only checks the summation of its own arcrole.
+		assert.Empty(t, content.CheckCalculations(networks, CalculationModeXBRL21))
+		for _, mode := range []CalculationMode{CalculationModeRound, CalculationModeTruncate} {
+			inconsistencies := content.CheckCalculations(networks, mode)
+			require.Len(t, inconsistencies, 1, mode)
+			assert.Equal(t, ArcRoleSummationItem11, inconsistencies[0].Summation.ArcRole)
+		}
+
+		complete.ArcRole, partial.ArcRole = ArcRoleSummationItem11, ArcRoleSummationItem
+		networks = []CalculationNetwork{{Summations: []Summation{complete, partial}}}
+		assert.Len(t, content.CheckCalculations(networks, CalculationModeXBRL21), 1)
+		assert.Empty(t, content.CheckCalculations(networks, CalculationModeRound))
+	})
+
+	t.Run("truncated tolerance", func(t *testing.T) {
+		fact := func(local, value string) Fact {
+			return Fact{Name: QName{Prefix: "ci", Local: local}, ID: local, ContextRef: "c1", UnitRef: stringPtr("usd"), Decimals: stringPtr("0"), ValueStr: stringPtr(value)}
+		}
+
+		// The sum of 50 and 50 can't be below 100, and the total of 97 can be up to 1 above it.
+		content := XBRL{Facts: []Fact{fact("Total", "97"), fact("A", "50"), fact("B", "50")}}
+		inconsistencies := content.CheckCalculations([]CalculationNetwork{{Summations: []Summation{summation("Total", "A", "B")}}}, CalculationModeTruncate)
+		require.Len(t, inconsistencies, 1)
+		assert.Equal(t, "[100, 102)", inconsistencies[0].ExpectedRange.String())
+		assert.Equal(t, "[97, 98)", inconsistencies[0].ActualRange.String())
+		assert.Equal(t, big.NewRat(1, 1), inconsistencies[0].Tolerance)
+	})
+
+	t.Run("truncated ranges", func(t *testing.T) {
+		testCases := []struct {
+			name       string
+			total      string
+			items      []string
+			weights    []int64
+			consistent bool
+		}{
+			{"below the sum", "98", []string{"50", "50"}, []int64{1, 1}, false},
+			{"lowest", "100", []string{"50", "50"}, []int64{1, 1}, true},
+			{"highest", "101", []string{"50", "50"}, []int64{1, 1}, true},
+			{"above the sum", "102", []string{"50", "50"}, []int64{1, 1}, false},
+			{"negative values", "-101", []string{"-50", "-50"}, []int64{1, 1}, true},
+			{"negative values below the sum", "-102", []string{"-50", "-50"}, []int64{1, 1}, false},
+			{"negative values above the sum", "-99", []string{"-50", "-50"}, []int64{1, 1}, false},
+			{"negative weight", "29", []string{"50", "20"}, []int64{1, -1}, true},
+			{"negative weight above the sum", "31", []string{"50", "20"}, []int64{1, -1}, false},
+			{"negative weight below the sum", "28", []string{"50", "20"}, []int64{1, -1}, false},
+			{"zero", "0", []string{"1", "1"}, []int64{1, -1}, true},
+			{"zero above the sum", "0", []string{"1"}, []int64{1}, false},
+		}
+
+		for _, testCase := range testCases {
+			t.Run(testCase.name, func(t *testing.T) {
+				fact := func(local, value string) Fact {
+					return Fact{Name: QName{Prefix: "ci", Local: local}, ContextRef: "c1", UnitRef: stringPtr("usd"), Decimals: stringPtr("0"), ValueStr: stringPtr(value)}
+				}
+
+				content := XBRL{Facts: []Fact{fact("Total", testCase.total)}}
+				summation := Summation{Total: QName{Prefix: "ci", Local: "Total"}}
+				for index, value := range testCase.items {
+					local := string(rune('A' + index))
+					content.Facts = append(content.Facts, fact(local, value))
+					summation.Items = append(summation.Items, SummationItem{Concept: QName{Prefix: "ci", Local: local}, Weight: big.NewRat(testCase.weights[index], 1)})
+				}
+
+				inconsistencies := content.CheckCalculations([]CalculationNetwork{{Summations: []Summation{summation}}}, CalculationModeTruncate)
+				assert.Equal(t, testCase.consistent, len(inconsistencies) == 0)
+			})
+		}
+	})
+
+	t.Run("extreme decimals", func(t *testing.T) {
+		fact := func(local, value, decimals string) Fact {
+			return Fact{Name: QName{Prefix: "ci", Local: local}, ID: local, ContextRef: "c1", UnitRef: stringPtr("usd"), Decimals: stringPtr(decimals), ValueStr: stringPtr(value)}
+		}
+
+		networks := []CalculationNetwork{{Summations: []Summation{
+			summation("Exact", "ExactA", "ExactB"),
+			summation("Vague", "VagueA", "VagueB"),
+		}}}
+
+		content := XBRL{Facts: []Fact{
+			fact("Exact", "3", "200000000"), fact("ExactA", "1", "200000000"), fact("ExactB", "1", "200000000"),
+			fact("Vague", "3", "-200000000"), fact("VagueA", "1", "0"), fact("VagueB", "1", "0"),
+		}}
+
+		for _, mode := range []CalculationMode{CalculationModeXBRL21, CalculationModeRound, CalculationModeTruncate} {
+			assert.Equal(t, []string{"Exact"}, totals(content.CheckCalculations(networks, mode)), "mode %v", mode)
+		}
+	})
+
+	t.Run("fractional tolerance", func(t *testing.T) {
+		inconsistency := CalculationInconsistency{
+			Total:     &Fact{XMLName: xml.Name{Local: "Total"}},
+			Expected:  big.NewRat(1, 3),
+			Actual:    big.NewRat(1, 1),
+			Tolerance: big.NewRat(5, 100),
+			Decimals:  Accuracy{Value: 2},
+		}
+
+		assert.Equal(t, "Total is 1 but its items sum to 1/3 (tolerance 0.05, decimals 2)", inconsistency.String())
+	})
+
+	t.Run("real-world xbrl", func(t *testing.T) {
+		f, err := os.Open("test_data/aapl-20210327_htm.xml")
+		require.NoError(t, err)
+		defer f.Close()
+
+		var content XBRL
+		require.NoError(t, xml.NewDecoder(f).Decode(&content))
+
+		networks := []CalculationNetwork{{
+			Role: "http://www.apple.com/role/CONDENSEDCONSOLIDATEDBALANCESHEETS",
+			Summations: []Summation{
+				{Total: QName{Prefix: "us-gaap", Local: "Assets"}, Items: []SummationItem{
+					{Concept: QName{Prefix: "us-gaap", Local: "AssetsCurrent"}, Weight: big.NewRat(1, 1)},
+					{Concept: QName{Prefix: "us-gaap", Local: "AssetsNoncurrent"}, Weight: big.NewRat(1, 1)},
+				}},
+				{Total: QName{Prefix: "us-gaap", Local: "Liabilities"}, Items: []SummationItem{
+					{Concept: QName{Prefix: "us-gaap", Local: "LiabilitiesCurrent"}, Weight: big.NewRat(1, 1)},
+					{Concept: QName{Prefix: "us-gaap", Local: "LiabilitiesNoncurrent"}, Weight: big.NewRat(1, 1)},
+				}},
+			},
+		}}
+
+		for _, mode := range []CalculationMode{CalculationModeXBRL21, CalculationModeRound, CalculationModeTruncate} {
+			assert.Empty(t, content.CheckCalculations(networks, mode), mode)
+		}
+
+		// Leaving out the non-current assets makes the total of both balance sheets wrong.
+		networks[0].Summations[0].Items = networks[0].Summations[0].Items[:1]
+		inconsistencies := content.CheckCalculations(networks, CalculationModeRound)
+		require.Len(t, inconsistencies, 2)
+		assert.Equal(t, big.NewRat(337158000000, 1), inconsistencies[0].Actual)
+		assert.Equal(t, big.NewRat(121465000000, 1), inconsistencies[0].Expected)
+		assert.Equal(t, big.NewRat(1000000, 1), inconsistencies[0].Tolerance)
+		assert.Equal(t, Accuracy{Value: -6}, inconsistencies[0].Decimals)
+	})
+}
@@ -2,7 +2,7 @@ package xbrl
 
 import "encoding/xml"
 
-// Context contains information about the Entity being described, the reporting Period, and the reporting Scenario (scenario is NOT implemented).
+// Context contains information about the Entity being described, the reporting Period, and the reporting Scenario.
 // All of which are necessary for understanding a business Fact captured as an XBRL item.
 // https://www.xbrl.org/Specification/XBRL-2.1/REC-2003-12-31/XBRL-2.1-REC-2003-12-31+corrected-errata-2013-02-20.html#_4.7
 type Context struct {
@@ -10,6 +10,15 @@ type Context struct {
 
 	Period Period `xml:"period"`
 	Entity Entity `xml:"entity"`
+
+	// Scenario is an optional container for additional information about the circumstances of the Facts in this Context,
+	// such as actual vs. budgeted amounts, or dimensions that some filers report here instead of in the Entity's Segments.
+	// Its sub-elements are parsed the same way as Entity.Segments.
+	// https://www.xbrl.org/Specification/XBRL-2.1/REC-2003-12-31/XBRL-2.1-REC-2003-12-31+corrected-errata-2013-02-20.html#_4.7.4
+	Scenario Segments `xml:"scenario"`
+
+	// Position is where the context is in the document it was decoded from.
+	Position Position `xml:"-"`
 }
 
 // Entity documents the business entity for a Context (business, government department, individual, etc.).
@@ -19,6 +28,18 @@ type Entity struct {
 	Segments   Segments   `xml:"segment"`
 }
 
+// UnmarshalXML implements xml.Unmarshaler for Entity.
+// The namespaces that the entity element declares are kept with its segments, so the QNames of their dimensions can be resolved.
+func (e *Entity) UnmarshalXML(d *xml.Decoder, start xml.StartElement) error {
+	type entity Entity
+	if err := d.DecodeElement((*entity)(e), &start); err != nil {
+		return err
+	}
+
+	e.Segments.declareNamespaces(start)
+	return nil
+}
+
 // Identifier specifies a scheme for identifying business entities and an identifier that follows the scheme.
 // https://www.xbrl.org/Specification/XBRL-2.1/REC-2003-12-31/XBRL-2.1-REC-2003-12-31+corrected-errata-2013-02-20.html#_4.7.3.1
 // For Example:
@@ -33,6 +54,7 @@ type Identifier struct {
 
 // Segments is a type alias for a slice of Segment structs.
 // It implements xml.Unmarshaller and puts and unmarshals any sub-elements as a Segment and puts it into the slice.
+// It's used for the contents of both the segment and the scenario elements of a Context.
 type Segments []Segment
 
 // Segment is an optional container for additional information used to identify a business segment more completely
@@ -43,10 +65,21 @@ type Segment struct {
 	XMLName    xml.Name
 	Attributes []xml.Attr `xml:",any,attr"`
 	Value      string     `xml:",chardata"`
+
+	// InnerXML is the raw content of the element, which keeps any nested elements that Value leaves out.
+	InnerXML string `xml:",innerxml"`
+
+	// Dimension is non-nil if this element is an xbrldi:explicitMember or xbrldi:typedMember.
+	// See Context.Dimensions() for all the dimensions of a Context.
+	Dimension *Dimension `xml:"-"`
+
+	// namespaces are the namespaces declared by this element and the ones around it within the context, outermost first.
+	// The namespaces declared outside of the context are added by Context.resolveNames().
+	namespaces namespaceScope
 }
 
 // UnmarshalXML implements xml.Unmarshaller for Segments.
-// It unmarshals any sub-elements as Segments and puts them into this slice.
+// It unmarshals any sub-elements as Segments and puts them into this slice, parsing any dimension members along the way.
 func (s *Segments) UnmarshalXML(d *xml.Decoder, start xml.StartElement) error {
 	var segmentsAnon struct {
 		Segments []Segment `xml:",any"`
@@ -56,10 +89,29 @@ func (s *Segments) UnmarshalXML(d *xml.Decoder, start xml.StartElement) error {
 		return err
 	}
 
+	for index := range segmentsAnon.Segments {
+		segment := &segmentsAnon.Segments[index]
+		segment.Dimension = parseDimension(*segment)
+		segment.namespaces = namespaceScope(nil).with(xml.StartElement{Attr: segment.Attributes})
+	}
+
 	*s = segmentsAnon.Segments
+	s.declareNamespaces(start)
 	return nil
 }
 
+// declareNamespaces adds the namespaces declared by start, an element around the segments, to their scopes.
+func (s Segments) declareNamespaces(start xml.StartElement) {
+	outer := namespaceScope(nil).with(start)
+	if len(outer) == 0 {
+		return
+	}
+
+	for index := range s {
+		s[index].namespaces = append(outer[:len(outer):len(outer)], s[index].namespaces...)
+	}
+}
+
 type PeriodType string
 
 // All the supported PeriodType values. See Period.Type() for more information.
@@ -71,11 +123,12 @@ const (
 )
 
 // Period contains an instant or interval of time for a Context.
+// The dates are kept as they appear in the document. Use StartTime(), EndTime() and InstantTime() to parse them.
 // https://www.xbrl.org/Specification/XBRL-2.1/REC-2003-12-31/XBRL-2.1-REC-2003-12-31+corrected-errata-2013-02-20.html#_4.7.2
 type Period struct {
-	// StartDate is non-nil and guaranteed to be before EndDate if Period.Type() returns Duration.
+	// StartDate is non-nil if Period.Type() returns Duration. Period.Validate() checks that it's before EndDate.
 	StartDate *string `xml:"startDate"`
-	// EndDate is non-nil and guaranteed to be after StartDate if Period.Type() returns Duration.
+	// EndDate is non-nil if Period.Type() returns Duration. Period.Validate() checks that it's after StartDate.
 	EndDate *string `xml:"endDate"`
 
 	// Instant is non-nil if Period.Type() returns Instant
@@ -82,5 +82,30 @@ func TestUnmarshalContext(t *testing.T) {
 		assert.Equal(t, PeriodTypeDuration, context.Period.Type())
 		assert.Equal(t, "2020-09-27", *context.Period.StartDate)
 		assert.Equal(t, "2021-03-27", *context.Period.EndDate)
+		assert.Empty(t, context.Scenario)
+	})
+
+	t.Run("instant period | has scenario", func(t *testing.T) {
+		// language=xml
+		contextXML := `<context id="c_scenario">
+    <entity>
+        <identifier scheme="http://standards.iso.org/iso/17442">5493001KJTIIGC8Y1R12</identifier>
+    </entity>
+    <period>
+        <instant>2021-12-31</instant>
+    </period>
+    <scenario>
+        <xbrldi:explicitMember dimension="ifrs-full:ComponentsOfEquityAxis">ifrs-full:RetainedEarningsMember</xbrldi:explicitMember>
+    </scenario>
+</context>`
+
+		var context Context
+		require.NoError(t, xml.Unmarshal([]byte(contextXML), &context))
+
+		assert.Empty(t, context.Entity.Segments)
+		require.Len(t, context.Scenario, 1)
+		assert.Equal(t, xml.Name{Space: "xbrldi", Local: "explicitMember"}, context.Scenario[0].XMLName)
+		assert.Equal(t, []xml.Attr{{Name: xml.Name{Local: "dimension"}, Value: "ifrs-full:ComponentsOfEquityAxis"}}, context.Scenario[0].Attributes)
+		assert.Equal(t, "ifrs-full:RetainedEarningsMember", context.Scenario[0].Value)
 	})
 }
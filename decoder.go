@@ -0,0 +1,152 @@
+package xbrl
+
+import (
+	"encoding/xml"
+	"io"
+)
+
+// Element is an element of an XBRL document returned by Decoder.Next().
+// It's one of *Context, *Unit, *Fact, *Tuple, *FootnoteLink, *SchemaRef, *LinkbaseRef, *RoleRef or *ArcRoleRef.
+type Element interface{}
+
+// Decoder reads the elements of an XBRL instance document one at a time,
+// so documents that are too large to fit in memory as a whole can still be processed, and processing can stop early.
+//
+// Elements are decoded exactly like unmarshalling into RawXBRL does, but nothing is kept after an element is returned.
+// That also means references between elements aren't checked, since facts can come before the contexts and units they reference.
+//
+// For example:
+//
+//	decoder := xbrl.NewDecoder(f)
+//	for {
+//		element, err := decoder.Next()
+//		if err == io.EOF {
+//			break
+//		} else if err != nil {
+//			return err
+//		}
+//
+//		switch e := element.(type) {
+//		case *xbrl.Context:
+//			...
+//		case *xbrl.Fact:
+//			...
+//		}
+//	}
+type Decoder struct {
+	decoder *xml.Decoder
+	scope   namespaceScope
+	strs    *stringInterner
+
+	started, done bool
+
+	// queue holds the elements that were decoded but not returned yet.
+	// A tuple is decoded as a whole, so it's queued along with all of its facts.
+	queue []Element
+}
+
+// NewDecoder creates a Decoder that reads an XBRL document from r.
+func NewDecoder(r io.Reader) *Decoder {
+	return NewXMLDecoder(xml.NewDecoder(r))
+}
+
+// NewXMLDecoder creates a Decoder that reads an XBRL document from an xml.Decoder,
+// which is useful to configure the xml.Decoder first (ie its CharsetReader).
+func NewXMLDecoder(d *xml.Decoder) *Decoder {
+	return &Decoder{decoder: d, strs: newStringInterner()}
+}
+
+// Next returns the next element of the document in document order, or io.EOF once the root element has ended.
+//
+// Facts inside of a tuple are returned before the tuple, with their Tuple field pointing at the tuple (or one of its descendants).
+// Only top-level tuples are returned, nested tuples are in their parent's Children.
+func (d *Decoder) Next() (Element, error) {
+	for len(d.queue) == 0 {
+		if d.done {
+			return nil, io.EOF
+		}
+
+		if err := d.decodeNext(); err != nil {
+			return nil, err
+		}
+	}
+
+	element := d.queue[0]
+	d.queue[0] = nil
+	d.queue = d.queue[1:]
+	return element, nil
+}
+
+// decodeNext reads the next token of the document and queues the elements it decodes, if any.
+func (d *Decoder) decodeNext() error {
+	pos := decoderPosition(d.decoder)
+	token, err := d.decoder.Token()
+	if err == io.EOF && d.started {
+		return io.ErrUnexpectedEOF
+	} else if err != nil {
+		return err
+	}
+
+	switch t := token.(type) {
+	case xml.StartElement:
+		if !d.started {
+			d.started = true
+			d.scope = namespaceScope{}.with(t)
+			return nil
+		}
+
+		var raw RawXBRL
+		if err := raw.decodeElement(d.decoder, t, pos, d.scope, d.strs); err != nil {
+			return err
+		}
+
+		raw.linkTupleFacts(raw.Tuples)
+		d.queue = rawElements(&raw)
+	case xml.EndElement:
+		d.done = true
+	}
+
+	return nil
+}
+
+// rawElements returns pointers to all the elements in raw, with facts before the tuples that contain them.
+func rawElements(raw *RawXBRL) []Element {
+	var elements []Element
+	for index := range raw.Contexts {
+		elements = append(elements, &raw.Contexts[index])
+	}
+
+	for index := range raw.Units {
+		elements = append(elements, &raw.Units[index])
+	}
+
+	for index := range raw.Facts {
+		elements = append(elements, &raw.Facts[index])
+	}
+
+	for _, tuple := range raw.Tuples {
+		elements = append(elements, tuple)
+	}
+
+	for index := range raw.FootnoteLinks {
+		elements = append(elements, &raw.FootnoteLinks[index])
+	}
+
+	for index := range raw.SchemaRefs {
+		elements = append(elements, &raw.SchemaRefs[index])
+	}
+
+	for index := range raw.LinkbaseRefs {
+		elements = append(elements, &raw.LinkbaseRefs[index])
+	}
+
+	for index := range raw.RoleRefs {
+		elements = append(elements, &raw.RoleRefs[index])
+	}
+
+	for index := range raw.ArcRoleRefs {
+		elements = append(elements, &raw.ArcRoleRefs[index])
+	}
+
+	return elements
+}
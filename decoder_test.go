@@ -0,0 +1,138 @@
+package xbrl
+
+import (
+	"bytes"
+	"encoding/xml"
+	"io"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDecoder(t *testing.T) {
+	t.Run("real-world xbrl from 2021", func(t *testing.T) {
+		xbrlBytes, err := os.ReadFile("test_data/aapl-20210327_htm.xml")
+		require.NoError(t, err)
+
+		var content XBRL
+		require.NoError(t, xml.Unmarshal(xbrlBytes, &content))
+
+		var contexts, units, schemaRefs int
+		var facts []Fact
+
+		decoder := NewDecoder(strings.NewReader(string(xbrlBytes)))
+		for {
+			element, err := decoder.Next()
+			if err == io.EOF {
+				break
+			}
+
+			require.NoError(t, err)
+			switch e := element.(type) {
+			case *Context:
+				contexts++
+				assert.Equal(t, content.ContextsByID[e.ID], *e)
+			case *Unit:
+				units++
+				assert.Equal(t, content.UnitsByID[e.ID], *e)
+			case *Fact:
+				facts = append(facts, *e)
+			case *SchemaRef:
+				schemaRefs++
+			default:
+				t.Fatalf("unexpected element: %T", element)
+			}
+		}
+
+		assert.Equal(t, 283, contexts)
+		assert.Equal(t, 9, units)
+		assert.Equal(t, 1, schemaRefs)
+		assert.Equal(t, content.Facts, facts)
+
+		// Next keeps returning io.EOF once the document has ended.
+		_, err = decoder.Next()
+		assert.Equal(t, io.EOF, err)
+	})
+
+	t.Run("stop early", func(t *testing.T) {
+		f, err := os.Open("test_data/aapl-20210327_htm.xml")
+		require.NoError(t, err)
+		defer f.Close()
+
+		decoder := NewDecoder(f)
+		for {
+			element, err := decoder.Next()
+			require.NoError(t, err)
+
+			if fact, ok := element.(*Fact); ok {
+				assert.Equal(t, "dei", fact.Name.Prefix)
+				break
+			}
+		}
+	})
+
+	t.Run("tuples", func(t *testing.T) {
+		// language=xml
+		xbrlXML := `<xbrl xmlns="http://www.xbrl.org/2003/instance" xmlns:my="http://example.com/taxonomy">
+    <my:Directors>
+        <my:Director>
+            <my:Name contextRef="c1">Jane Doe</my:Name>
+        </my:Director>
+        <my:Count contextRef="c1" unitRef="pure" decimals="0">1</my:Count>
+    </my:Directors>
+</xbrl>`
+
+		decoder := NewXMLDecoder(xml.NewDecoder(strings.NewReader(xbrlXML)))
+
+		name, err := decoder.Next()
+		require.NoError(t, err)
+		require.IsType(t, &Fact{}, name)
+		assert.Equal(t, "Jane Doe", name.(*Fact).Value())
+
+		count, err := decoder.Next()
+		require.NoError(t, err)
+		require.IsType(t, &Fact{}, count)
+
+		directors, err := decoder.Next()
+		require.NoError(t, err)
+		require.IsType(t, &Tuple{}, directors)
+		assert.Same(t, directors, count.(*Fact).Tuple)
+		assert.Same(t, directors.(*Tuple).Tuples()[0], name.(*Fact).Tuple)
+
+		_, err = decoder.Next()
+		assert.Equal(t, io.EOF, err)
+	})
+
+	t.Run("truncated document", func(t *testing.T) {
+		decoder := NewDecoder(strings.NewReader(`<xbrl><my:Fact contextRef="c1">text</my:Fact>`))
+
+		_, err := decoder.Next()
+		require.NoError(t, err)
+
+		_, err = decoder.Next()
+		assert.Error(t, err)
+		assert.NotEqual(t, io.EOF, err)
+	})
+}
+
+func BenchmarkDecoder(b *testing.B) {
+	xbrlBytes, err := os.ReadFile("test_data/aapl-20210327_htm.xml")
+	require.NoError(b, err)
+
+	b.ReportAllocs()
+	b.SetBytes(int64(len(xbrlBytes)))
+
+	for i := 0; i < b.N; i++ {
+		decoder := NewDecoder(bytes.NewReader(xbrlBytes))
+		for {
+			if _, err := decoder.Next(); err == io.EOF {
+				break
+			} else if err != nil {
+				b.Fatal(err)
+			}
+		}
+	}
+}
@@ -0,0 +1,110 @@
+package xbrl
+
+import "strings"
+
+type DimensionType string
+
+// All the supported DimensionType values.
+const (
+	// DimensionTypeExplicit is a dimension whose member is one of a fixed set of domain members defined in a taxonomy.
+	// For example: <xbrldi:explicitMember dimension="us-gaap:StatementClassOfStockAxis">us-gaap:CommonStockMember</xbrldi:explicitMember>
+	DimensionTypeExplicit DimensionType = "explicit"
+
+	// DimensionTypeTyped is a dimension whose member is an arbitrary XML element, ie a date or an identifier.
+	// For example: <xbrldi:typedMember dimension="my:ContractAxis"><my:ContractId>A-123</my:ContractId></xbrldi:typedMember>
+	DimensionTypeTyped DimensionType = "typed"
+)
+
+// Dimension is an XBRL Dimensions 1.0 member found in the segment or scenario of a Context.
+// Dimensions break a Fact down further than its entity and period, ie revenue by product line or by geographic region.
+// https://www.xbrl.org/specification/dimensions/rec-2012-01-25/dimensions-rec-2006-09-18+corrected-errata-2012-01-25-clean.html#sec-contexts
+type Dimension struct {
+	Type DimensionType
+
+	// Dimension is the name of the dimension (axis) taken from the dimension attribute.
+	Dimension QName
+
+	// Member is the domain member for explicit dimensions. It's empty for typed dimensions.
+	Member QName
+
+	// TypedMember is the inner XML of a typedMember element, with surrounding whitespace removed.
+	// It's empty for explicit dimensions.
+	TypedMember string
+}
+
+// parseDimension returns the Dimension that a segment or scenario sub-element represents,
+// or nil if it isn't an explicitMember or typedMember element with a dimension attribute.
+//
+// The element namespace isn't checked, since plenty of documents use the xbrldi prefix without declaring it.
+func parseDimension(segment Segment) *Dimension {
+	var dimensionType DimensionType
+	switch segment.XMLName.Local {
+	case "explicitMember":
+		dimensionType = DimensionTypeExplicit
+	case "typedMember":
+		dimensionType = DimensionTypeTyped
+	default:
+		return nil
+	}
+
+	for _, attr := range segment.Attributes {
+		if attr.Name.Local != "dimension" {
+			continue
+		}
+
+		dimension := &Dimension{Type: dimensionType, Dimension: ParseQName(attr.Value)}
+		if dimensionType == DimensionTypeExplicit {
+			dimension.Member = ParseQName(segment.Value)
+		} else {
+			dimension.TypedMember = strings.TrimSpace(segment.InnerXML)
+		}
+
+		return dimension
+	}
+
+	return nil
+}
+
+// resolveNames resolves the QNames of the dimensions in the Context against the namespaces in scope, which are the ones
+// declared on the context element and around it, and the ones declared between the context element and each dimension.
+func (c *Context) resolveNames(scope namespaceScope) {
+	for _, segments := range []Segments{c.Entity.Segments, c.Scenario} {
+		for _, segment := range segments {
+			if segment.Dimension != nil {
+				inner := append(scope[:len(scope):len(scope)], segment.namespaces...)
+				segment.Dimension.Dimension = inner.resolve(segment.Dimension.Dimension)
+				if segment.Dimension.Type == DimensionTypeExplicit {
+					segment.Dimension.Member = inner.resolve(segment.Dimension.Member)
+				}
+			}
+		}
+	}
+}
+
+// Dimensions returns the dimensions in the Context's segment followed by those in its scenario, in document order.
+func (c Context) Dimensions() []Dimension {
+	var dimensions []Dimension
+	for _, segments := range []Segments{c.Entity.Segments, c.Scenario} {
+		for _, segment := range segments {
+			if segment.Dimension != nil {
+				dimensions = append(dimensions, *segment.Dimension)
+			}
+		}
+	}
+
+	return dimensions
+}
+
+// HasDimensions returns true if the Context has at least one dimension in its segment or scenario.
+// Contexts without dimensions are the ones that default (total) values are reported in.
+func (c Context) HasDimensions() bool {
+	for _, segments := range []Segments{c.Entity.Segments, c.Scenario} {
+		for _, segment := range segments {
+			if segment.Dimension != nil {
+				return true
+			}
+		}
+	}
+
+	return false
+}
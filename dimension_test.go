@@ -0,0 +1,132 @@
+package xbrl
+
+import (
+	"encoding/xml"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestContextDimensions(t *testing.T) {
+	t.Run("explicit and typed members", func(t *testing.T) {
+		// language=xml
+		contextXML := `<context id="c1">
+    <entity>
+        <identifier scheme="http://www.sec.gov/CIK">0000320193</identifier>
+        <segment>
+            <xbrldi:explicitMember dimension="us-gaap:StatementClassOfStockAxis"> us-gaap:CommonStockMember </xbrldi:explicitMember>
+            <xbrldi:typedMember dimension="my:ContractAxis">
+                <my:ContractId>A-123</my:ContractId>
+            </xbrldi:typedMember>
+            <my:cool_segment>I follow my own rules</my:cool_segment>
+        </segment>
+    </entity>
+    <period>
+        <instant>2021-03-27</instant>
+    </period>
+    <scenario>
+        <xbrldi:explicitMember dimension="srt:ProductOrServiceAxis">us-gaap:ServiceMember</xbrldi:explicitMember>
+    </scenario>
+</context>`
+
+		var context Context
+		require.NoError(t, xml.Unmarshal([]byte(contextXML), &context))
+		require.True(t, context.HasDimensions())
+
+		dimensions := context.Dimensions()
+		require.Len(t, dimensions, 3)
+
+		assert.Equal(t, Dimension{
+			Type:      DimensionTypeExplicit,
+			Dimension: QName{Prefix: "us-gaap", Local: "StatementClassOfStockAxis"},
+			Member:    QName{Prefix: "us-gaap", Local: "CommonStockMember"},
+		}, dimensions[0])
+
+		assert.Equal(t, Dimension{
+			Type:        DimensionTypeTyped,
+			Dimension:   QName{Prefix: "my", Local: "ContractAxis"},
+			TypedMember: "<my:ContractId>A-123</my:ContractId>",
+		}, dimensions[1])
+
+		assert.Equal(t, "srt:ProductOrServiceAxis", dimensions[2].Dimension.String())
+		assert.Equal(t, "us-gaap:ServiceMember", dimensions[2].Member.String())
+
+		assert.Nil(t, context.Entity.Segments[2].Dimension)
+		assert.Equal(t, "I follow my own rules", context.Entity.Segments[2].Value)
+	})
+
+	t.Run("no dimensions", func(t *testing.T) {
+		// language=xml
+		contextXML := `<context id="c1">
+    <entity>
+        <identifier scheme="http://www.sec.gov/CIK">0000320193</identifier>
+        <segment>
+            <my:cool_segment>I follow my own rules</my:cool_segment>
+        </segment>
+    </entity>
+    <period>
+        <instant>2021-03-27</instant>
+    </period>
+</context>`
+
+		var context Context
+		require.NoError(t, xml.Unmarshal([]byte(contextXML), &context))
+		assert.False(t, context.HasDimensions())
+		assert.Empty(t, context.Dimensions())
+	})
+
+	t.Run("namespaces declared inside of the context", func(t *testing.T) {
+		// language=xml
+		const doc = `<xbrl xmlns:xbrldi="http://xbrl.org/2006/xbrldi" xmlns:a="http://a">
+    <context id="c1">
+        <entity xmlns:e="http://e">
+            <identifier scheme="http://www.sec.gov/CIK">0000320193</identifier>
+            <segment xmlns:b="http://b">
+                <xbrldi:explicitMember dimension="b:Axis">e:Member</xbrldi:explicitMember>
+                <xbrldi:explicitMember xmlns:c="http://c" dimension="c:Axis">a:Member</xbrldi:explicitMember>
+            </segment>
+        </entity>
+        <period><instant>2021-03-27</instant></period>
+        <scenario xmlns:a="http://redeclared">
+            <xbrldi:explicitMember dimension="a:Axis">b:Member</xbrldi:explicitMember>
+        </scenario>
+    </context>
+    <context id="c2">
+        <entity>
+            <identifier scheme="http://www.sec.gov/CIK">0000320193</identifier>
+            <segment>
+                <xbrldi:explicitMember xmlns:other="http://b" xmlns:e2="http://e" dimension="other:Axis">e2:Member</xbrldi:explicitMember>
+                <xbrldi:explicitMember xmlns:c="http://c" dimension="c:Axis">a:Member</xbrldi:explicitMember>
+            </segment>
+        </entity>
+        <period><instant>2021-03-27</instant></period>
+        <scenario xmlns:a="http://redeclared">
+            <xbrldi:explicitMember dimension="a:Axis">b:Member</xbrldi:explicitMember>
+        </scenario>
+    </context>
+</xbrl>`
+
+		var content XBRL
+		require.NoError(t, xml.Unmarshal([]byte(doc), &content))
+
+		dimensions := content.ContextsByID["c1"].Dimensions()
+		require.Len(t, dimensions, 3)
+		assert.Equal(t, QName{Space: "http://b", Local: "Axis", Prefix: "b"}, dimensions[0].Dimension)
+		assert.Equal(t, QName{Space: "http://e", Local: "Member", Prefix: "e"}, dimensions[0].Member)
+		assert.Equal(t, QName{Space: "http://c", Local: "Axis", Prefix: "c"}, dimensions[1].Dimension)
+		assert.Equal(t, QName{Space: "http://a", Local: "Member", Prefix: "a"}, dimensions[1].Member)
+
+		// b isn't declared around the scenario, and a is redeclared.
+		assert.Equal(t, QName{Space: "http://redeclared", Local: "Axis", Prefix: "a"}, dimensions[2].Dimension)
+		assert.Equal(t, QName{Local: "Member", Prefix: "b"}, dimensions[2].Member)
+
+		assert.True(t, content.ContextsByID["c1"].Equal(content.ContextsByID["c2"]))
+	})
+}
+
+func TestParseQName(t *testing.T) {
+	assert.Equal(t, QName{Prefix: "iso4217", Local: "USD"}, ParseQName(" iso4217:USD\n"))
+	assert.Equal(t, QName{Local: "shares"}, ParseQName("shares"))
+	assert.Equal(t, "shares", QName{Local: "shares"}.String())
+}
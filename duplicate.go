@@ -0,0 +1,195 @@
+package xbrl
+
+import "strings"
+
+type DuplicateClass string
+
+// All the DuplicateClass values, following the XBRL Duplicate Facts guidance.
+// https://www.xbrl.org/WGN/xbrl-duplicates/WGN-2017-01-11/xbrl-duplicates-WGN-2017-01-11.html
+const (
+	// DuplicateClassComplete means all the facts in the group have the same value and the same accuracy (decimals),
+	// so all but one of them can be dropped without losing anything. Inline XBRL filings often repeat facts like this.
+	DuplicateClassComplete DuplicateClass = "complete"
+
+	// DuplicateClassConsistent means the numeric facts in the group have different accuracies,
+	// but their values agree once they're rounded to the decimals of the least accurate fact of each pair,
+	// ie 1,234,567 reported in units (decimals="0") and 1,235,000 reported in thousands (decimals="-3").
+	// The most accurate fact can be kept.
+	DuplicateClassConsistent DuplicateClass = "consistent"
+
+	// DuplicateClassInconsistent means the facts in the group disagree, which is usually an error in the document.
+	// Non-numeric facts are inconsistent as soon as their values differ.
+	DuplicateClassInconsistent DuplicateClass = "inconsistent"
+)
+
+// DuplicateGroup is a set of facts that report the same thing: the same concept in equivalent contexts and units,
+// with the same language for non-numeric facts and the same parent tuple.
+type DuplicateGroup struct {
+	// Concept is the name of the facts in the group.
+	Concept QName
+
+	// Facts are the duplicate facts in document order. They point to facts in XBRL.Facts.
+	Facts []*Fact
+
+	Class DuplicateClass
+}
+
+// duplicateKey identifies the facts that are duplicates of each other.
+type duplicateKey struct {
+	concept string
+	context string
+	unit    string
+	lang    string
+	tuple   *Tuple
+}
+
+// DuplicateFacts returns the groups of two or more facts that are duplicates of each other, ordered by their first fact.
+//
+// Contexts and units are compared by what they contain rather than by ID, so facts referencing two identical contexts are still duplicates.
+// Facts whose context or unit doesn't exist are compared by ID instead. Facts that aren't valid (see Fact.IsValid()) are ignored.
+func (x XBRL) DuplicateFacts() []DuplicateGroup {
+	keys := x.referenceKeys()
+
+	var duplicateKeys []duplicateKey
+	groups := make(map[duplicateKey][]*Fact)
+	for index := range x.Facts {
+		fact := &x.Facts[index]
+		if !fact.IsValid() {
+			continue
+		}
+
+		key := duplicateKey{concept: qnameKey(factConcept(*fact)), tuple: fact.Tuple}
+		key.context, key.unit = keys.of(*fact)
+		if fact.UnitRef == nil {
+			// Language tags are case-insensitive.
+			key.lang = strings.ToLower(strings.TrimSpace(fact.Lang))
+		}
+
+		if _, exists := groups[key]; !exists {
+			duplicateKeys = append(duplicateKeys, key)
+		}
+
+		groups[key] = append(groups[key], fact)
+	}
+
+	var duplicates []DuplicateGroup
+	for _, key := range duplicateKeys {
+		facts := groups[key]
+		if len(facts) < 2 {
+			continue
+		}
+
+		duplicates = append(duplicates, DuplicateGroup{
+			Concept: factConcept(*facts[0]),
+			Facts:   facts,
+			Class:   classifyDuplicates(facts),
+		})
+	}
+
+	return duplicates
+}
+
+// factConcept returns the name of a fact, using XMLName if it wasn't decoded as part of a document.
+func factConcept(fact Fact) QName {
+	if fact.Name.Local != "" {
+		return fact.Name
+	}
+
+	return QName{Space: fact.XMLName.Space, Local: fact.XMLName.Local}
+}
+
+// classifyDuplicates returns the DuplicateClass of a group of duplicate facts.
+// Nil facts are complete duplicates of each other, but inconsistent with facts that have a value.
+func classifyDuplicates(facts []*Fact) DuplicateClass {
+	nils := 0
+	for _, fact := range facts {
+		if fact.Type() == FactTypeNil {
+			nils++
+		}
+	}
+
+	switch {
+	case nils == len(facts):
+		return DuplicateClassComplete
+	case nils > 0:
+		return DuplicateClassInconsistent
+	}
+
+	switch facts[0].Type() {
+	case FactTypeNonFraction, FactTypeFraction:
+		return classifyNumericDuplicates(facts)
+	default:
+		for _, fact := range facts[1:] {
+			if fact.Value() != facts[0].Value() {
+				return DuplicateClassInconsistent
+			}
+		}
+
+		return DuplicateClassComplete
+	}
+}
+
+// classifyNumericDuplicates returns the DuplicateClass of a group of duplicate numeric facts.
+// Facts whose value or decimals can't be parsed are only complete duplicates if they're written exactly the same.
+func classifyNumericDuplicates(facts []*Fact) DuplicateClass {
+	complete := true
+	for i, fact := range facts {
+		value, err := fact.RatValue()
+		if err != nil {
+			return classifyUnparsedDuplicates(facts)
+		}
+
+		decimals, err := fact.DecimalsValue()
+		if err != nil {
+			return classifyUnparsedDuplicates(facts)
+		}
+
+		for _, other := range facts[:i] {
+			otherValue, _ := other.RatValue()
+			otherDecimals, _ := other.DecimalsValue()
+
+			if decimals != otherDecimals || value.Cmp(otherValue) != 0 {
+				complete = false
+			}
+
+			// Round both values to the decimals of the least accurate of the two.
+			lowest := decimals
+			if !otherDecimals.Infinite && (decimals.Infinite || otherDecimals.Value < decimals.Value) {
+				lowest = otherDecimals
+			}
+
+			if RoundRat(value, lowest).Cmp(RoundRat(otherValue, lowest)) != 0 {
+				return DuplicateClassInconsistent
+			}
+		}
+	}
+
+	if complete {
+		return DuplicateClassComplete
+	}
+
+	return DuplicateClassConsistent
+}
+
+// classifyUnparsedDuplicates classifies numeric duplicates by comparing their values and decimals as they're written.
+func classifyUnparsedDuplicates(facts []*Fact) DuplicateClass {
+	first := facts[0]
+	for _, fact := range facts[1:] {
+		if !equalStringPtrs(fact.ValueStr, first.ValueStr) || !equalStringPtrs(fact.NumeratorStr, first.NumeratorStr) ||
+			!equalStringPtrs(fact.DenominatorStr, first.DenominatorStr) || !equalStringPtrs(fact.Decimals, first.Decimals) ||
+			!equalStringPtrs(fact.Precision, first.Precision) {
+			return DuplicateClassInconsistent
+		}
+	}
+
+	return DuplicateClassComplete
+}
+
+// equalStringPtrs returns true if both a and b are nil, or they point to equal strings.
+func equalStringPtrs(a, b *string) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+
+	return *a == *b
+}
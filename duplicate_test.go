@@ -0,0 +1,155 @@
+package xbrl
+
+import (
+	"encoding/xml"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDuplicateFacts(t *testing.T) {
+	// language=xml
+	const doc = `<xbrl xmlns:ci="http://www.xbrl.org/us/gaap/ci/2003/usfr-ci-2003" xmlns:iso4217="http://www.xbrl.org/2003/iso4217"
+    xmlns:currency="http://www.xbrl.org/2003/iso4217" xmlns:xbrldi="http://xbrl.org/2006/xbrldi" xml:lang="en-US">
+    <context id="c1">
+        <entity><identifier scheme="http://www.sec.gov/CIK">0000320193</identifier></entity>
+        <period><instant>2021-03-27</instant></period>
+    </context>
+    <context id="c1-copy">
+        <entity><identifier scheme="http://www.sec.gov/CIK"> 0000320193 </identifier></entity>
+        <period><instant>2021-03-28T00:00:00</instant></period>
+    </context>
+    <context id="c1-segment">
+        <entity>
+            <identifier scheme="http://www.sec.gov/CIK">0000320193</identifier>
+            <segment><xbrldi:explicitMember dimension="ci:ProductAxis">ci:IPhoneMember</xbrldi:explicitMember></segment>
+        </entity>
+        <period><instant>2021-03-27</instant></period>
+    </context>
+    <unit id="usd"><measure>iso4217:USD</measure></unit>
+    <unit id="usd-copy"><measure>currency:USD</measure></unit>
+
+    <ci:assets contextRef="c1" unitRef="usd" decimals="-6">100000000</ci:assets>
+    <ci:assets contextRef="c1-copy" unitRef="usd-copy" decimals="-6">100000000</ci:assets>
+    <ci:assets contextRef="c1-segment" unitRef="usd" decimals="-6">100000000</ci:assets>
+
+    <ci:liabilities contextRef="c1" unitRef="usd" decimals="0">1234567</ci:liabilities>
+    <ci:liabilities contextRef="c1" unitRef="usd" decimals="-3">1235000</ci:liabilities>
+    <ci:liabilities contextRef="c1" unitRef="usd" decimals="-6">1000000</ci:liabilities>
+
+    <ci:equity contextRef="c1" unitRef="usd" decimals="0">100</ci:equity>
+    <ci:equity contextRef="c1" unitRef="usd" decimals="0">200</ci:equity>
+
+    <ci:revenue contextRef="c1" unitRef="usd" decimals="0">100</ci:revenue>
+    <ci:revenue contextRef="c1" unitRef="usd" xsi:nil="true"/>
+
+    <ci:description contextRef="c1">Apple Inc.</ci:description>
+    <ci:description contextRef="c1" xml:lang="EN-us">Apple Inc.</ci:description>
+    <ci:description contextRef="c1" xml:lang="fr">Apple Inc.</ci:description>
+
+    <ci:address contextRef="c1">One Apple Park Way</ci:address>
+    <ci:address contextRef="c1">1 Infinite Loop</ci:address>
+
+    <ci:tuple><ci:name contextRef="c1">Tim</ci:name></ci:tuple>
+    <ci:tuple><ci:name contextRef="c1">Tim</ci:name></ci:tuple>
+</xbrl>`
+
+	var content XBRL
+	require.NoError(t, xml.Unmarshal([]byte(doc), &content))
+	require.NoError(t, content.Validate())
+
+	groups := content.DuplicateFacts()
+	require.Len(t, groups, 6)
+
+	t.Run("complete", func(t *testing.T) {
+		group := groups[0]
+		assert.Equal(t, "assets", group.Concept.Local)
+		assert.Equal(t, DuplicateClassComplete, group.Class)
+		require.Len(t, group.Facts, 2)
+		assert.Same(t, &content.Facts[0], group.Facts[0])
+		assert.Same(t, &content.Facts[1], group.Facts[1])
+	})
+
+	t.Run("consistent", func(t *testing.T) {
+		group := groups[1]
+		assert.Equal(t, "liabilities", group.Concept.Local)
+		assert.Equal(t, DuplicateClassConsistent, group.Class)
+		assert.Len(t, group.Facts, 3)
+	})
+
+	t.Run("inconsistent", func(t *testing.T) {
+		assert.Equal(t, "equity", groups[2].Concept.Local)
+		assert.Equal(t, DuplicateClassInconsistent, groups[2].Class)
+
+		assert.Equal(t, "revenue", groups[3].Concept.Local)
+		assert.Equal(t, DuplicateClassInconsistent, groups[3].Class)
+	})
+
+	t.Run("language", func(t *testing.T) {
+		group := groups[4]
+		assert.Equal(t, "description", group.Concept.Local)
+		assert.Equal(t, DuplicateClassComplete, group.Class)
+		require.Len(t, group.Facts, 2)
+		assert.Equal(t, "en-US", group.Facts[0].Lang)
+		assert.Equal(t, "EN-us", group.Facts[1].Lang)
+
+		assert.Equal(t, "address", groups[5].Concept.Local)
+		assert.Equal(t, DuplicateClassInconsistent, groups[5].Class)
+	})
+
+	t.Run("real-world xbrl", func(t *testing.T) {
+		f, err := os.Open("test_data/aapl-20210327_htm.xml")
+		require.NoError(t, err)
+		defer f.Close()
+
+		var content XBRL
+		require.NoError(t, xml.NewDecoder(f).Decode(&content))
+
+		classes := make(map[DuplicateClass]int)
+		for _, group := range content.DuplicateFacts() {
+			classes[group.Class]++
+			if group.Class == DuplicateClassConsistent {
+				assert.Equal(t, "DebtInstrumentCarryingAmount", group.Concept.Local)
+			}
+		}
+
+		assert.Equal(t, map[DuplicateClass]int{DuplicateClassComplete: 74, DuplicateClassConsistent: 1}, classes)
+	})
+}
+
+func TestClassifyDuplicates(t *testing.T) {
+	fact := func(value, decimals string) *Fact {
+		return &Fact{ContextRef: "c1", UnitRef: stringPtr("u1"), Decimals: stringPtr(decimals), ValueStr: stringPtr(value)}
+	}
+
+	testCases := []struct {
+		name     string
+		facts    []*Fact
+		expected DuplicateClass
+	}{
+		{"same value and decimals", []*Fact{fact("1.5", "1"), fact("1.50", "1")}, DuplicateClassComplete},
+		{"same value and different decimals", []*Fact{fact("1000", "0"), fact("1000", "-3")}, DuplicateClassConsistent},
+		{"rounds half to even", []*Fact{fact("2500", "0"), fact("2000", "-3")}, DuplicateClassConsistent},
+		{"rounds to the least accurate of each pair", []*Fact{fact("1.04", "2"), fact("1.0", "1"), fact("1", "0")}, DuplicateClassConsistent},
+		{"infinite decimals", []*Fact{fact("1234", "INF"), fact("1000", "-3")}, DuplicateClassConsistent},
+		{"different values", []*Fact{fact("1.5", "1"), fact("1.6", "1")}, DuplicateClassInconsistent},
+		{"rounded values differ", []*Fact{fact("1600", "0"), fact("1000", "-3")}, DuplicateClassInconsistent},
+		{"unparsed values", []*Fact{fact("1,000", "0"), fact("1,000", "0")}, DuplicateClassComplete},
+		{"different unparsed values", []*Fact{fact("1,000", "0"), fact("1000", "0")}, DuplicateClassInconsistent},
+		{"extreme decimals", []*Fact{fact("1.5", "200000000"), fact("1.50", "200000000")}, DuplicateClassComplete},
+		{"extreme negative decimals", []*Fact{fact("1.5", "-200000000"), fact("1000", "0")}, DuplicateClassConsistent},
+		{"nil facts", []*Fact{{ContextRef: "c1", Nil: boolPtr(true)}, {ContextRef: "c1", Nil: boolPtr(true)}}, DuplicateClassComplete},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			assert.Equal(t, testCase.expected, classifyDuplicates(testCase.facts))
+		})
+	}
+}
+
+func boolPtr(value bool) *bool {
+	return &value
+}
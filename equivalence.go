@@ -0,0 +1,177 @@
+package xbrl
+
+import (
+	"sort"
+	"strings"
+	"time"
+)
+
+// Equal returns true if the contexts are s-equal (structure-equal) as defined in section 4.10 of the spec,
+// which means they have the same entity, period, segment and scenario, whatever their IDs are.
+// See Key() for how they're compared.
+// https://www.xbrl.org/Specification/XBRL-2.1/REC-2003-12-31/XBRL-2.1-REC-2003-12-31+corrected-errata-2013-02-20.html#_4.10
+func (c Context) Equal(other Context) bool {
+	return c.Key() == other.Key()
+}
+
+// Key returns a canonical key for the context, which is the same for contexts that are Equal() and can be used as a map key.
+// The ID of the context isn't part of the key. Contexts are compared the following way:
+//   - The identifier's scheme and value are compared after collapsing whitespace.
+//   - Period dates are compared as points in time, so an instant of 2021-03-27 is equal to one of 2021-03-28T00:00:00.
+//     Dates that can't be parsed are compared as they're written.
+//   - Dimensions are compared by their resolved names, so the prefixes don't matter, and in any order as in XBRL Dimensions 1.0.
+//     Other segment and scenario elements are compared by their name, attributes and content, also in any order.
+//
+// The format of the key isn't specified and could change, so it shouldn't be stored.
+func (c Context) Key() string {
+	var builder strings.Builder
+	builder.WriteString(collapseWhitespace(c.Entity.Identifier.Scheme))
+	builder.WriteByte(0)
+	builder.WriteString(collapseWhitespace(c.Entity.Identifier.Value))
+	builder.WriteByte(0)
+	builder.WriteString(periodKey(c.Period))
+
+	for _, segments := range []Segments{c.Entity.Segments, c.Scenario} {
+		builder.WriteByte(0)
+		builder.WriteString(segmentsKey(segments))
+	}
+
+	return builder.String()
+}
+
+// periodKey returns a string that's the same for periods that represent the same instant or duration.
+// Dates are compared as points in time, so 2021-03-27 and 2021-03-28T00:00:00 are the same instant.
+// Dates that can't be parsed are compared as they're written.
+func periodKey(p Period) string {
+	switch p.Type() {
+	case PeriodTypeInstant:
+		return "instant " + periodDateKey(*p.Instant, true)
+	case PeriodTypeDuration:
+		return "duration " + periodDateKey(*p.StartDate, false) + " " + periodDateKey(*p.EndDate, true)
+	case PeriodTypeForever:
+		return "forever"
+	default:
+		return "invalid"
+	}
+}
+
+// periodDateKey returns the point in time of a period date in UTC, or the collapsed value if it can't be parsed.
+func periodDateKey(value string, endOfDay bool) string {
+	parsed, err := parsePeriodDate(value, endOfDay)
+	if err != nil {
+		return collapseWhitespace(value)
+	}
+
+	return parsed.UTC().Format(time.RFC3339Nano)
+}
+
+// segmentsKey returns a string that's the same for segments or scenarios with the same elements in any order.
+// Dimensions are compared by their resolved names (or typed member value), and other elements by their name, attributes and content.
+func segmentsKey(segments Segments) string {
+	keys := make([]string, 0, len(segments))
+	for _, segment := range segments {
+		if dimension := segment.Dimension; dimension != nil {
+			member := qnameKey(dimension.Member)
+			if dimension.Type == DimensionTypeTyped {
+				member = collapseWhitespace(dimension.TypedMember)
+			}
+
+			keys = append(keys, string(dimension.Type)+" "+qnameKey(dimension.Dimension)+"="+member)
+			continue
+		}
+
+		attributes := make([]string, 0, len(segment.Attributes))
+		for _, attr := range segment.Attributes {
+			// Namespace declarations aren't part of the element's value.
+			if attr.Name.Space == "xmlns" || (attr.Name.Space == "" && attr.Name.Local == "xmlns") {
+				continue
+			}
+
+			attributes = append(attributes, attr.Name.Space+" "+attr.Name.Local+"="+attr.Value)
+		}
+
+		sort.Strings(attributes)
+		keys = append(keys, "{"+segment.XMLName.Space+"}"+segment.XMLName.Local+" "+strings.Join(attributes, " ")+" "+collapseWhitespace(segment.InnerXML))
+	}
+
+	sort.Strings(keys)
+	return strings.Join(keys, "\x01")
+}
+
+// Equal returns true if the units are u-equal (unit-equal) as defined in section 4.10 of the spec,
+// which means they have the same measures, in any order, whatever their IDs are.
+// Measures are compared by their resolved names, so iso4217:USD equals currency:USD if both prefixes are bound to the same namespace.
+// https://www.xbrl.org/Specification/XBRL-2.1/REC-2003-12-31/XBRL-2.1-REC-2003-12-31+corrected-errata-2013-02-20.html#_4.10
+func (u Unit) Equal(other Unit) bool {
+	return u.Key() == other.Key()
+}
+
+// Key returns a canonical key for the unit, which is the same for units that are Equal() and can be used as a map key.
+// The ID of the unit isn't part of the key, and the format of the key could change, so it shouldn't be stored.
+func (u Unit) Key() string {
+	if u.Divide != nil {
+		return measuresKey(u.Divide.Numerator) + " / " + measuresKey(u.Divide.Denominator)
+	}
+
+	return measuresKey(u.Measures)
+}
+
+// measuresKey returns a string that's the same for products of the same measures in any order.
+func measuresKey(measures Measures) string {
+	keys := make([]string, 0, len(measures))
+	for _, measure := range measures {
+		keys = append(keys, qnameKey(measure.resolvedName()))
+	}
+
+	sort.Strings(keys)
+	return strings.Join(keys, " * ")
+}
+
+// qnameKey returns a string that's the same for QNames that are Equal().
+func qnameKey(q QName) string {
+	if q.Space != "" {
+		return "{" + q.Space + "}" + q.Local
+	}
+
+	return q.Prefix + ":" + q.Local
+}
+
+// referenceKeys holds the Key() of every context and unit of a document by ID.
+type referenceKeys struct {
+	contexts map[string]string
+	units    map[string]string
+}
+
+func (x XBRL) referenceKeys() referenceKeys {
+	keys := referenceKeys{
+		contexts: make(map[string]string, len(x.ContextsByID)),
+		units:    make(map[string]string, len(x.UnitsByID)),
+	}
+
+	for id, context := range x.ContextsByID {
+		keys.contexts[id] = context.Key()
+	}
+
+	for id, unit := range x.UnitsByID {
+		keys.units[id] = unit.Key()
+	}
+
+	return keys
+}
+
+// of returns the keys of the context and unit that fact references, so facts in equal contexts and units get the same keys.
+// References to contexts or units that don't exist are compared by ID instead. The unit key is empty for facts without a unit.
+func (k referenceKeys) of(fact Fact) (context, unit string) {
+	var exists bool
+	if context, exists = k.contexts[fact.ContextRef]; !exists {
+		context = "id " + fact.ContextRef
+	}
+
+	if fact.UnitRef != nil {
+		if unit, exists = k.units[*fact.UnitRef]; !exists {
+			unit = "id " + *fact.UnitRef
+		}
+	}
+
+	return context, unit
+}
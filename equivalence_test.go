@@ -0,0 +1,228 @@
+package xbrl
+
+import (
+	"encoding/xml"
+	"fmt"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// language=xml
+const equivalenceXBRL = `<xbrl xmlns:ci="http://www.xbrl.org/us/gaap/ci/2003/usfr-ci-2003" xmlns:other="http://www.xbrl.org/us/gaap/ci/2003/usfr-ci-2003"
+    xmlns:iso4217="http://www.xbrl.org/2003/iso4217" xmlns:currency="http://www.xbrl.org/2003/iso4217" xmlns:xbrldi="http://xbrl.org/2006/xbrldi">
+    <context id="c1">
+        <entity><identifier scheme="http://www.sec.gov/CIK">0000320193</identifier></entity>
+        <period><startDate>2020-12-27</startDate><endDate>2021-03-27</endDate></period>
+    </context>
+    <context id="c1-copy">
+        <entity><identifier scheme="http://www.sec.gov/CIK">
+            0000320193
+        </identifier></entity>
+        <period><startDate>2020-12-27T00:00:00</startDate><endDate>2021-03-28T00:00:00Z</endDate></period>
+    </context>
+    <context id="c2">
+        <entity><identifier scheme="http://www.sec.gov/CIK">0000320193</identifier></entity>
+        <period><instant>2021-03-27</instant></period>
+    </context>
+    <context id="c2-instant">
+        <entity><identifier scheme="http://www.sec.gov/CIK">0000320193</identifier></entity>
+        <period><instant>2021-03-28T00:00:00</instant></period>
+    </context>
+    <context id="c3">
+        <entity>
+            <identifier scheme="http://www.sec.gov/CIK">0000320193</identifier>
+            <segment>
+                <xbrldi:explicitMember dimension="ci:ProductAxis">ci:IPhoneMember</xbrldi:explicitMember>
+                <xbrldi:explicitMember dimension="ci:RegionAxis">ci:EuropeMember</xbrldi:explicitMember>
+            </segment>
+        </entity>
+        <period><instant>2021-03-27</instant></period>
+    </context>
+    <context id="c3-reordered">
+        <entity>
+            <identifier scheme="http://www.sec.gov/CIK">0000320193</identifier>
+            <segment>
+                <xbrldi:explicitMember dimension="other:RegionAxis">other:EuropeMember</xbrldi:explicitMember>
+                <xbrldi:explicitMember dimension="other:ProductAxis">other:IPhoneMember</xbrldi:explicitMember>
+            </segment>
+        </entity>
+        <period><instant>2021-03-27</instant></period>
+    </context>
+    <context id="c3-scenario">
+        <entity><identifier scheme="http://www.sec.gov/CIK">0000320193</identifier></entity>
+        <period><instant>2021-03-27</instant></period>
+        <scenario>
+            <xbrldi:explicitMember dimension="ci:ProductAxis">ci:IPhoneMember</xbrldi:explicitMember>
+            <xbrldi:explicitMember dimension="ci:RegionAxis">ci:EuropeMember</xbrldi:explicitMember>
+        </scenario>
+    </context>
+    <unit id="usd"><measure>iso4217:USD</measure></unit>
+    <unit id="usd-copy"><measure>currency:USD</measure></unit>
+    <unit id="usd-shares"><measure>iso4217:USD</measure><measure>shares</measure></unit>
+    <unit id="shares-usd"><measure>shares</measure><measure>currency:USD</measure></unit>
+    <unit id="usd-per-share">
+        <divide>
+            <unitNumerator><measure>iso4217:USD</measure></unitNumerator>
+            <unitDenominator><measure>shares</measure></unitDenominator>
+        </divide>
+    </unit>
+    <unit id="share-per-usd">
+        <divide>
+            <unitNumerator><measure>shares</measure></unitNumerator>
+            <unitDenominator><measure>iso4217:USD</measure></unitDenominator>
+        </divide>
+    </unit>
+
+    <ci:revenue contextRef="c1" unitRef="usd" decimals="0">1</ci:revenue>
+    <ci:revenue contextRef="c1-copy" unitRef="usd-copy" decimals="0">1</ci:revenue>
+    <ci:assets contextRef="c2-instant" unitRef="usd-copy" decimals="0">2</ci:assets>
+    <ci:eps contextRef="c3-reordered" unitRef="usd-per-share" decimals="2">1.41</ci:eps>
+</xbrl>`
+
+func TestContextEqual(t *testing.T) {
+	var raw RawXBRL
+	require.NoError(t, xml.Unmarshal([]byte(equivalenceXBRL), &raw))
+
+	contexts := NewProcessedXBRL(raw).ContextsByID
+
+	testCases := []struct {
+		a, b  string
+		equal bool
+	}{
+		{"c1", "c1-copy", true},
+		{"c2", "c2-instant", true},
+		{"c3", "c3-reordered", true},
+		{"c1", "c2", false},
+		{"c2", "c3", false},
+		{"c3", "c3-scenario", false},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.a+" and "+testCase.b, func(t *testing.T) {
+			a, b := contexts[testCase.a], contexts[testCase.b]
+			assert.Equal(t, testCase.equal, a.Equal(b))
+			assert.Equal(t, testCase.equal, b.Equal(a))
+			assert.Equal(t, testCase.equal, a.Key() == b.Key())
+		})
+	}
+
+	t.Run("unparsed dates", func(t *testing.T) {
+		a := Context{Period: Period{Instant: stringPtr("yesterday")}}
+		b := Context{Period: Period{Instant: stringPtr(" yesterday ")}}
+		c := Context{Period: Period{Instant: stringPtr("today")}}
+
+		assert.True(t, a.Equal(b))
+		assert.False(t, a.Equal(c))
+	})
+
+	t.Run("typed members", func(t *testing.T) {
+		// language=xml
+		contextXML := `<context id="%s" xmlns:xbrldi="http://xbrl.org/2006/xbrldi" xmlns:my="http://example.com/my">
+    <entity><identifier scheme="http://www.sec.gov/CIK">0000320193</identifier></entity>
+    <period><forever/></period>
+    <scenario><xbrldi:typedMember dimension="my:ContractAxis">%s</xbrldi:typedMember></scenario>
+</context>`
+
+		var a, b, c Context
+		require.NoError(t, xml.Unmarshal([]byte(fmt.Sprintf(contextXML, "a", "<my:ContractId>A-123</my:ContractId>")), &a))
+		require.NoError(t, xml.Unmarshal([]byte(fmt.Sprintf(contextXML, "b", "\n  <my:ContractId>A-123</my:ContractId>\n")), &b))
+		require.NoError(t, xml.Unmarshal([]byte(fmt.Sprintf(contextXML, "c", "<my:ContractId>B-456</my:ContractId>")), &c))
+
+		assert.True(t, a.Equal(b))
+		assert.False(t, a.Equal(c))
+	})
+}
+
+func TestUnitEqual(t *testing.T) {
+	var raw RawXBRL
+	require.NoError(t, xml.Unmarshal([]byte(equivalenceXBRL), &raw))
+
+	units := NewProcessedXBRL(raw).UnitsByID
+
+	testCases := []struct {
+		a, b  string
+		equal bool
+	}{
+		{"usd", "usd-copy", true},
+		{"usd-shares", "shares-usd", true},
+		{"usd", "usd-shares", false},
+		{"usd-per-share", "share-per-usd", false},
+		{"usd-per-share", "usd-shares", false},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.a+" and "+testCase.b, func(t *testing.T) {
+			a, b := units[testCase.a], units[testCase.b]
+			assert.Equal(t, testCase.equal, a.Equal(b))
+			assert.Equal(t, testCase.equal, a.Key() == b.Key())
+		})
+	}
+}
+
+func TestMergeEquivalent(t *testing.T) {
+	t.Run("contexts and units", func(t *testing.T) {
+		var raw RawXBRL
+		require.NoError(t, xml.Unmarshal([]byte(equivalenceXBRL), &raw))
+
+		processed := NewProcessedXBRL(raw, MergeEquivalentContexts(), MergeEquivalentUnits())
+		require.NoError(t, processed.Validate())
+
+		assert.Len(t, processed.ContextsByID, 4)
+		for _, id := range []string{"c1", "c2", "c3", "c3-scenario"} {
+			assert.Contains(t, processed.ContextsByID, id)
+		}
+
+		assert.Len(t, processed.UnitsByID, 4)
+		for _, id := range []string{"usd", "usd-shares", "usd-per-share", "share-per-usd"} {
+			assert.Contains(t, processed.UnitsByID, id)
+		}
+
+		require.Len(t, processed.Facts, 4)
+		assert.Equal(t, "c1", processed.Facts[1].ContextRef)
+		assert.Equal(t, "usd", *processed.Facts[1].UnitRef)
+		assert.Equal(t, "c2", processed.Facts[2].ContextRef)
+		assert.Equal(t, "usd", *processed.Facts[2].UnitRef)
+		assert.Equal(t, "c3", processed.Facts[3].ContextRef)
+		assert.Equal(t, "usd-per-share", *processed.Facts[3].UnitRef)
+	})
+
+	t.Run("only contexts", func(t *testing.T) {
+		var raw RawXBRL
+		require.NoError(t, xml.Unmarshal([]byte(equivalenceXBRL), &raw))
+
+		processed := NewProcessedXBRL(raw, MergeEquivalentContexts())
+		assert.Len(t, processed.ContextsByID, 4)
+		assert.Len(t, processed.UnitsByID, 6)
+		assert.Equal(t, "usd-copy", *processed.Facts[1].UnitRef)
+	})
+
+	t.Run("shared unit refs", func(t *testing.T) {
+		var raw RawXBRL
+		require.NoError(t, xml.Unmarshal([]byte(equivalenceXBRL), &raw))
+
+		// The unitRef of the second and third facts is the same interned string, which must not be changed for the third fact.
+		sharedRef := raw.Facts[1].UnitRef
+		require.Same(t, sharedRef, raw.Facts[2].UnitRef)
+
+		NewProcessedXBRL(raw, MergeEquivalentUnits())
+		assert.Equal(t, "usd-copy", *sharedRef)
+	})
+
+	t.Run("nothing to merge", func(t *testing.T) {
+		f, err := os.Open("test_data/aapl-20210327_htm.xml")
+		require.NoError(t, err)
+		defer f.Close()
+
+		var raw RawXBRL
+		require.NoError(t, xml.NewDecoder(f).Decode(&raw))
+
+		unmerged := NewProcessedXBRL(raw)
+		processed := NewProcessedXBRL(raw, MergeEquivalentContexts(), MergeEquivalentUnits())
+		require.NoError(t, processed.Validate())
+		assert.Equal(t, len(unmerged.ContextsByID), len(processed.ContextsByID))
+		assert.Equal(t, len(unmerged.UnitsByID), len(processed.UnitsByID))
+	})
+}
@@ -3,7 +3,10 @@ package xbrl
 import (
 	"encoding/xml"
 	"errors"
+	"fmt"
+	"math/big"
 	"strconv"
+	"strings"
 )
 
 type FactType string
@@ -55,6 +58,11 @@ var ErrNonNumericFactType = errors.New("fact is not of type FactTypeFraction or
 type Fact struct {
 	XMLName xml.Name
 
+	// Name is the concept of the fact with its namespace resolved and its original prefix, ie "us-gaap:Revenues".
+	// It's set when the fact is decoded as part of an XBRL, RawXBRL or InlineXBRL document, which know the namespace declarations.
+	// Use Name.Equal() to compare concepts, since filers are free to pick their own prefixes.
+	Name QName `xml:"-"`
+
 	// ID uniquely identifies a fact within an XBRL document.
 	// The spec does not require an ID attribute, but many items have an ID attribute, which is why it's included in this model.
 	ID string `xml:"id,attr"`
@@ -67,12 +75,18 @@ type Fact struct {
 
 	// UnitRef is the ID of the unit in the XBRL document that this fact is expressed in.
 	// It is non-nil for numeric facts only.
+	//
+	// When facts are decoded as part of a document, UnitRef, Precision, Decimals and Nil point to values that are shared between
+	// all the facts with the same value, and ValueStr points into a block shared with the facts next to it, which saves a lot of allocations.
+	// Assign a new pointer instead of changing the value they point to.
 	UnitRef *string `xml:"unitRef,attr"`
 
 	// Precision conveys the arithmetic precision of a measurement.
 	// It can be either a non-negative integer or the special value "INF", which represents infinite precision.
 	// If this is a numeric fact but NOT a fraction type, Precision will be non-nil if Decimals is nil,
 	//
+	// Use PrecisionValue() for the parsed value, which is inferred from Decimals if Precision is nil.
+	//
 	// Examples and more info here:
 	// https://www.xbrl.org/Specification/XBRL-2.1/REC-2003-12-31/XBRL-2.1-REC-2003-12-31+corrected-errata-2013-02-20.html#_4.6.4
 	Precision *string `xml:"precision,attr"`
@@ -81,18 +95,185 @@ type Fact struct {
 	// It can be either an integer (positive or negative) or the special value "INF", which represents accuracy to infinite decimal places.
 	// If this is a numeric fact but NOT a fraction type, Decimals will be non-nil if Precision is nil,
 	//
+	// Use DecimalsValue() for the parsed value, which is inferred from Precision if Decimals is nil.
+	//
 	// Examples and more info here:
 	// https://www.xbrl.org/Specification/XBRL-2.1/REC-2003-12-31/XBRL-2.1-REC-2003-12-31+corrected-errata-2013-02-20.html#_4.6.5
 	Decimals *string `xml:"decimals,attr"`
 
 	// ValueStr will be non-nil unless this is a numeric fraction type Fact.
 	// Use NumericValue() to easily get the numeric value that this fact represents, regardless of whether or not it's a fraction type.
+	// For inline facts this is the value after the format, scale and sign were applied. See Inline for the displayed value.
 	ValueStr *string `xml:",chardata"`
 
 	// Numerator and Denominator will be non-nil values if this is a fraction type Fact.
 	// Use NumericValue() to easily get the numeric value that this fact represents, regardless of whether or not it's a fraction type.
-	Numerator   *float64 `xml:"numerator"`
-	Denominator *float64 `xml:"denominator"`
+	Numerator   *float64 `xml:"-"`
+	Denominator *float64 `xml:"-"`
+
+	// NumeratorStr and DenominatorStr are the numerator and denominator of a fraction type Fact as they appear in the document.
+	// Unlike Numerator and Denominator they don't lose any precision. See RatValue().
+	NumeratorStr   *string `xml:"numerator"`
+	DenominatorStr *string `xml:"denominator"`
+
+	// Lang is the language of the fact's value from its xml:lang attribute, ie "en-US". It's only meaningful for non-numeric facts.
+	// When the fact is decoded as part of a document, it's inherited from the enclosing elements if the fact doesn't have one.
+	Lang string `xml:"lang,attr"`
+
+	// Tuple is the tuple that contains this fact, or nil if this fact isn't part of a tuple.
+	Tuple *Tuple `xml:"-"`
+
+	// Inline is non-nil if this fact was read from an Inline XBRL document.
+	// It holds the displayed value of the fact and the format, scale and sign that were applied to it.
+	Inline *InlineAttributes `xml:"-"`
+
+	// Position is where the fact is in the document it was decoded from. For inline facts, it's the position of the ix element.
+	Position Position `xml:"-"`
+}
+
+// UnmarshalXML implements xml.Unmarshaler.
+// It decodes the fields of the fact by their xml tags, and parses the numerator and denominator of fraction facts into Numerator and Denominator.
+func (f *Fact) UnmarshalXML(d *xml.Decoder, start xml.StartElement) error {
+	fact, err := decodeFact(d, start, nil)
+	if err != nil {
+		return err
+	}
+
+	*f = fact
+	return nil
+}
+
+// decodeFact decodes a fact element the same way xml.Decoder.DecodeElement would decode it using the xml tags of Fact.
+// Facts are by far the most common element in a document, so they're decoded by hand instead of through reflection,
+// and the strings that repeat across facts (references, decimals, names) are interned with strs, which can be nil.
+func decodeFact(d *xml.Decoder, start xml.StartElement, strs *stringInterner) (Fact, error) {
+	fact := Fact{XMLName: xml.Name{Space: *strs.intern(start.Name.Space), Local: *strs.intern(start.Name.Local)}}
+
+	// Like DecodeElement, attributes are matched by their local name only, and the last one wins if there are duplicates.
+	for _, attr := range start.Attr {
+		switch attr.Name.Local {
+		case "id":
+			fact.ID = attr.Value
+		case "nil":
+			isNil, err := strconv.ParseBool(strings.TrimSpace(attr.Value))
+			if err != nil {
+				return Fact{}, err
+			}
+
+			fact.Nil = sharedBool(isNil)
+		case "contextRef":
+			fact.ContextRef = *strs.intern(attr.Value)
+		case "unitRef":
+			fact.UnitRef = strs.intern(attr.Value)
+		case "precision":
+			fact.Precision = strs.intern(attr.Value)
+		case "decimals":
+			fact.Decimals = strs.intern(attr.Value)
+		case "lang":
+			fact.Lang = *strs.intern(attr.Value)
+		}
+	}
+
+	// There's usually a single chunk of character data, which can be copied straight into the value.
+	var value string
+	var numerator, denominator *string
+	for {
+		token, err := d.Token()
+		if err != nil {
+			return Fact{}, err
+		}
+
+		switch t := token.(type) {
+		case xml.CharData:
+			value += string(t)
+		case xml.StartElement:
+			switch t.Name.Local {
+			case "numerator":
+				if numerator, err = decodeText(d, strs); err != nil {
+					return Fact{}, err
+				}
+			case "denominator":
+				if denominator, err = decodeText(d, strs); err != nil {
+					return Fact{}, err
+				}
+			default:
+				if err := d.Skip(); err != nil {
+					return Fact{}, err
+				}
+			}
+		case xml.EndElement:
+			fact.ValueStr = strs.value(value)
+			if err := fact.setFractionTerms(numerator, denominator); err != nil {
+				return Fact{}, err
+			}
+
+			return fact, nil
+		}
+	}
+}
+
+// decodeText returns the character data of the element whose start tag was just read, skipping any child elements.
+func decodeText(d *xml.Decoder, strs *stringInterner) (*string, error) {
+	var text string
+	for {
+		token, err := d.Token()
+		if err != nil {
+			return nil, err
+		}
+
+		switch t := token.(type) {
+		case xml.CharData:
+			text += string(t)
+		case xml.StartElement:
+			if err := d.Skip(); err != nil {
+				return nil, err
+			}
+		case xml.EndElement:
+			return strs.value(text), nil
+		}
+	}
+}
+
+// setFractionTerms sets the string and float64 values of the Numerator and Denominator of a fraction type Fact.
+// The terms must be valid xsd:decimal values. Nil terms are left nil.
+func (f *Fact) setFractionTerms(numerator, denominator *string) error {
+	f.NumeratorStr, f.DenominatorStr = numerator, denominator
+	f.Numerator, f.Denominator = nil, nil
+
+	if numerator != nil {
+		value, err := parseNumeric(*numerator, NumericItemTypeDecimal)
+		if err != nil {
+			return fmt.Errorf("fact %s: invalid numerator: %w", f.describe(), err)
+		}
+
+		f.Numerator = &value
+	}
+
+	if denominator != nil {
+		value, err := parseNumeric(*denominator, NumericItemTypeDecimal)
+		if err != nil {
+			return fmt.Errorf("fact %s: invalid denominator: %w", f.describe(), err)
+		}
+
+		f.Denominator = &value
+	}
+
+	return nil
+}
+
+// describe returns the name of the fact (and its ID and position if it has them) for error messages,
+// ie "us-gaap:Revenues (id f-1) at line 12, column 5 (offset 345)".
+func (f Fact) describe() string {
+	description := f.Name.String()
+	if f.Name.Local == "" {
+		description = f.XMLName.Local
+	}
+
+	if f.ID != "" {
+		description += " (id " + f.ID + ")"
+	}
+
+	return description + describePosition(f.Position)
 }
 
 // Type returns the type of this Fact. See the comments on the various FactTypes for more information.
@@ -143,20 +324,100 @@ func (f Fact) IsValid() bool {
 }
 
 // NumericValue attempts to return the numeric value this fact represents.
-// This function returns
+// Use RatValue() instead when the value needs to be exact, ie when summing or comparing monetary values.
+//
+// The value is parsed following the lexical rules of xsd:decimal, which most numeric item types are derived from,
+// so surrounding whitespace and a leading '+' are allowed, but exponents, INF and NaN are not.
+// Use NumericValueAs() for items derived from xsd:double or xsd:float.
+//
 // If this fact is a fraction type, this function returns the value of numerator / denominator.
 // Note that fraction type facts generally cannot be precisely represented as a float64 and may have some rounding error.
 func (f Fact) NumericValue() (float64, error) {
+	return f.NumericValueAs(NumericItemTypeDecimal)
+}
+
+// NumericValueAs is like NumericValue(), but parses the value following the lexical rules of the given item type.
+// The numerator and denominator of fraction facts are always decimals, so itemType doesn't affect them.
+func (f Fact) NumericValueAs(itemType NumericItemType) (float64, error) {
 	switch f.Type() {
 	case FactTypeFraction:
 		return *f.Numerator / *f.Denominator, nil
 	case FactTypeNonFraction:
-		return strconv.ParseFloat(*f.ValueStr, 64)
+		if f.ValueStr == nil {
+			return 0, fmt.Errorf("fact %s: missing value", f.describe())
+		}
+
+		value, err := parseNumeric(*f.ValueStr, itemType)
+		if err != nil {
+			return 0, fmt.Errorf("fact %s: %w", f.describe(), err)
+		}
+
+		return value, nil
 	default:
 		return 0, ErrNonNumericFactType
 	}
 }
 
+// RatValue returns the exact numeric value this fact represents as a big.Rat.
+// Unlike NumericValue(), the value doesn't lose any precision: fraction facts are kept as an exact ratio,
+// and non-fraction values like 0.1 are represented exactly, so the results can safely be summed and compared.
+//
+// The value must be a valid xsd:decimal, since INF and NaN can't be represented by a big.Rat.
+// The terms of a fraction are parsed from NumeratorStr and DenominatorStr, or taken from Numerator and Denominator
+// for facts that weren't decoded, although those are only as exact as a float64.
+func (f Fact) RatValue() (*big.Rat, error) {
+	switch f.Type() {
+	case FactTypeFraction:
+		numerator, err := fractionTerm(f.NumeratorStr, f.Numerator)
+		if err != nil {
+			return nil, fmt.Errorf("fact %s: invalid numerator: %w", f.describe(), err)
+		}
+
+		denominator, err := fractionTerm(f.DenominatorStr, f.Denominator)
+		if err != nil {
+			return nil, fmt.Errorf("fact %s: invalid denominator: %w", f.describe(), err)
+		}
+
+		if denominator.Sign() == 0 {
+			return nil, fmt.Errorf("fact %s: fraction has a zero denominator", f.describe())
+		}
+
+		return numerator.Quo(numerator, denominator), nil
+	case FactTypeNonFraction:
+		value, err := parseRat(f.ValueStr)
+		if err != nil {
+			return nil, fmt.Errorf("fact %s: %w", f.describe(), err)
+		}
+
+		return value, nil
+	default:
+		return nil, ErrNonNumericFactType
+	}
+}
+
+// parseRat parses an xsd:decimal value into an exact big.Rat.
+func parseRat(value *string) (*big.Rat, error) {
+	if value == nil {
+		return nil, errors.New("missing value")
+	}
+
+	return ParseXSDDecimal(*value)
+}
+
+// fractionTerm returns the exact value of the term of a fraction, from its lexical value if it has one.
+func fractionTerm(str *string, value *float64) (*big.Rat, error) {
+	if str != nil || value == nil {
+		return parseRat(str)
+	}
+
+	rat := new(big.Rat)
+	if rat.SetFloat64(*value) == nil {
+		return nil, fmt.Errorf("%v isn't a finite number", *value)
+	}
+
+	return rat, nil
+}
+
 // Value returns the ValueStr of this Fact, or empty string if f.ValueStr is nil.
 func (f Fact) Value() string {
 	if f.ValueStr != nil {
@@ -1,7 +1,13 @@
 package xbrl
 
 import (
+	"bytes"
 	"encoding/xml"
+	"io"
+	"math"
+	"math/big"
+	"os"
+	"strings"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -106,5 +112,212 @@ func TestUnmarshalFact(t *testing.T) {
 		val, err := fact.NumericValue()
 		require.NoError(t, err)
 		assert.EqualValues(t, 1.0/3.0, val)
+
+		rat, err := fact.RatValue()
+		require.NoError(t, err)
+		assert.Equal(t, "1/3", rat.String())
+		require.NotNil(t, fact.NumeratorStr)
+		assert.Equal(t, "1", *fact.NumeratorStr)
+	})
+
+	t.Run("invalid fraction numerator", func(t *testing.T) {
+		// language=xml
+		factXML := `<myTaxonomy:oneThird unitRef="u1" contextRef="numC1">
+	<numerator>one</numerator>
+	<denominator>3</denominator>
+</myTaxonomy:oneThird>`
+
+		var fact Fact
+		assert.Error(t, xml.Unmarshal([]byte(factXML), &fact))
+	})
+}
+
+func TestDecodeFact(t *testing.T) {
+	// reflectFact decodes a fact with encoding/xml's reflection using the xml tags of Fact, which decodeFact must match.
+	reflectFact := func(t *testing.T, d *xml.Decoder, start xml.StartElement) Fact {
+		type fact Fact
+		var decoded fact
+		require.NoError(t, d.DecodeElement(&decoded, &start))
+		require.NoError(t, (*Fact)(&decoded).setFractionTerms(decoded.NumeratorStr, decoded.DenominatorStr))
+		return Fact(decoded)
+	}
+
+	// decodeBoth decodes the first element of factXML with both decodeFact and reflectFact.
+	decodeBoth := func(t *testing.T, factXML string) (Fact, Fact) {
+		var facts [2]Fact
+		for index := range facts {
+			d := xml.NewDecoder(strings.NewReader(factXML))
+			token, err := d.Token()
+			require.NoError(t, err)
+
+			if index == 0 {
+				facts[index], err = decodeFact(d, token.(xml.StartElement), newStringInterner())
+				require.NoError(t, err)
+			} else {
+				facts[index] = reflectFact(t, d, token.(xml.StartElement))
+			}
+		}
+
+		return facts[0], facts[1]
+	}
+
+	t.Run("matches reflection", func(t *testing.T) {
+		for _, factXML := range []string{
+			// language=xml
+			`<ci:capitalLeases id="id123" contextRef="c1" unitRef="u1" precision="3">727432</ci:capitalLeases>`,
+			// language=xml
+			`<myns:sillyFact contextRef="c1" xsi:nil=" true "/>`,
+			// language=xml
+			`<ci:textBlock contextRef="c1">Some <b>bold</b> text &amp; <![CDATA[raw <text>]]></ci:textBlock>`,
+			// language=xml
+			`<ci:dup contextRef="c1" decimals="2" other:decimals="-3">1</ci:dup>`,
+			// language=xml
+			`<ci:fraction contextRef="c1" unitRef="u1"><numerator>1<x>2</x></numerator><denominator> 3 </denominator></ci:fraction>`,
+		} {
+			decoded, reflected := decodeBoth(t, factXML)
+			assert.Equal(t, reflected, decoded, factXML)
+		}
+	})
+
+	t.Run("matches reflection for a whole document", func(t *testing.T) {
+		xbrlBytes, err := os.ReadFile("test_data/aapl-20210327_htm.xml")
+		require.NoError(t, err)
+
+		var processed XBRL
+		require.NoError(t, xml.Unmarshal(xbrlBytes, &processed))
+
+		d := xml.NewDecoder(bytes.NewReader(xbrlBytes))
+		var reflected []Fact
+		for {
+			token, err := d.Token()
+			if err == io.EOF {
+				break
+			}
+			require.NoError(t, err)
+
+			if start, ok := token.(xml.StartElement); ok && inlineAttrPtr(start, "contextRef") != nil {
+				reflected = append(reflected, reflectFact(t, d, start))
+			}
+		}
+
+		require.Len(t, processed.Facts, len(reflected))
+		for index, fact := range processed.Facts {
+			// Name, Position and an inherited Lang come from the document, and can't be found with reflection.
+			fact.Name, fact.Position = QName{}, Position{}
+			if reflected[index].Lang == "" {
+				fact.Lang = ""
+			}
+
+			assert.Equal(t, reflected[index], fact)
+		}
+	})
+
+	t.Run("interns repeated values", func(t *testing.T) {
+		strs := newStringInterner()
+		var facts []Fact
+		for _, factXML := range []string{
+			// language=xml
+			`<ci:first contextRef="c1" unitRef="usd" decimals="-6">1</ci:first>`,
+			// language=xml
+			`<ci:second contextRef="c1" unitRef="usd" decimals="-6">2</ci:second>`,
+		} {
+			d := xml.NewDecoder(strings.NewReader(factXML))
+			token, err := d.Token()
+			require.NoError(t, err)
+
+			fact, err := decodeFact(d, token.(xml.StartElement), strs)
+			require.NoError(t, err)
+			facts = append(facts, fact)
+		}
+
+		assert.Same(t, facts[0].UnitRef, facts[1].UnitRef)
+		assert.Same(t, facts[0].Decimals, facts[1].Decimals)
+		assert.NotSame(t, facts[0].ValueStr, facts[1].ValueStr)
+	})
+
+	t.Run("invalid nil", func(t *testing.T) {
+		// language=xml
+		factXML := `<myns:sillyFact contextRef="c1" xsi:nil="maybe"/>`
+
+		var fact Fact
+		assert.Error(t, xml.Unmarshal([]byte(factXML), &fact))
+	})
+
+	t.Run("truncated fact", func(t *testing.T) {
+		// language=xml
+		factXML := `<ci:capitalLeases contextRef="c1" unitRef="u1" decimals="0">727`
+
+		var fact Fact
+		assert.Error(t, xml.Unmarshal([]byte(factXML), &fact))
+	})
+}
+
+func TestFactRatValue(t *testing.T) {
+	t.Run("decimals are exact", func(t *testing.T) {
+		var sum big.Rat
+		for _, value := range []string{"0.1", "0.2"} {
+			fact := Fact{ContextRef: "c1", UnitRef: stringPtr("u1"), Decimals: stringPtr("1"), ValueStr: stringPtr(value)}
+
+			rat, err := fact.RatValue()
+			require.NoError(t, err)
+			sum.Add(&sum, rat)
+		}
+
+		assert.Zero(t, sum.Cmp(big.NewRat(3, 10)))
+	})
+
+	t.Run("large values", func(t *testing.T) {
+		fact := Fact{ContextRef: "c1", UnitRef: stringPtr("u1"), Decimals: stringPtr("0"), ValueStr: stringPtr(" 123456789012345678901234567 ")}
+
+		rat, err := fact.RatValue()
+		require.NoError(t, err)
+		assert.Equal(t, "123456789012345678901234567", rat.FloatString(0))
+	})
+
+	t.Run("fraction with decimal terms", func(t *testing.T) {
+		fact := Fact{ContextRef: "c1", UnitRef: stringPtr("u1")}
+		require.NoError(t, fact.setFractionTerms(stringPtr("0.1"), stringPtr("3")))
+
+		rat, err := fact.RatValue()
+		require.NoError(t, err)
+		assert.Equal(t, "1/30", rat.String())
+	})
+
+	t.Run("hand-built fraction", func(t *testing.T) {
+		numerator, denominator := 1.0, 4.0
+		fact := Fact{ContextRef: "c1", UnitRef: stringPtr("u1"), Numerator: &numerator, Denominator: &denominator}
+
+		rat, err := fact.RatValue()
+		require.NoError(t, err)
+		assert.Equal(t, "1/4", rat.String())
+
+		infinite := math.Inf(1)
+		fact.Numerator = &infinite
+		_, err = fact.RatValue()
+		assert.Error(t, err)
+	})
+
+	t.Run("zero denominator", func(t *testing.T) {
+		fact := Fact{ContextRef: "c1", UnitRef: stringPtr("u1")}
+		require.NoError(t, fact.setFractionTerms(stringPtr("1"), stringPtr("0")))
+
+		_, err := fact.RatValue()
+		assert.Error(t, err)
+	})
+
+	t.Run("invalid values", func(t *testing.T) {
+		for _, value := range []string{"1/3", "abc", ""} {
+			fact := Fact{ContextRef: "c1", UnitRef: stringPtr("u1"), Decimals: stringPtr("0"), ValueStr: stringPtr(value)}
+
+			_, err := fact.RatValue()
+			assert.Error(t, err, value)
+		}
+	})
+
+	t.Run("non-numeric fact", func(t *testing.T) {
+		fact := Fact{ContextRef: "c1", ValueStr: stringPtr("text")}
+
+		_, err := fact.RatValue()
+		assert.ErrorIs(t, err, ErrNonNumericFactType)
 	})
 }
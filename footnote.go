@@ -0,0 +1,106 @@
+package xbrl
+
+import "strings"
+
+// ArcRoleFactFootnote is the standard arcrole of a footnoteArc that links a fact to a footnote.
+const ArcRoleFactFootnote = "http://www.xbrl.org/2003/arcrole/fact-footnote"
+
+// FootnoteLink is an extended link that attaches footnotes to facts.
+// Facts are referenced by Locators, and footnoteArcs connect the labels of those Locators to the labels of Footnotes.
+//
+// For example:
+//
+//	<link:footnoteLink xlink:type="extended" xlink:role="http://www.xbrl.org/2003/role/link">
+//	    <link:loc xlink:type="locator" xlink:href="#f-1" xlink:label="fact1"/>
+//	    <link:footnote xlink:type="resource" xlink:label="footnote1" xlink:role="http://www.xbrl.org/2003/role/footnote" xml:lang="en-US">Includes discontinued operations.</link:footnote>
+//	    <link:footnoteArc xlink:type="arc" xlink:from="fact1" xlink:to="footnote1" xlink:arcrole="http://www.xbrl.org/2003/arcrole/fact-footnote"/>
+//	</link:footnoteLink>
+//
+// https://www.xbrl.org/Specification/XBRL-2.1/REC-2003-12-31/XBRL-2.1-REC-2003-12-31+corrected-errata-2013-02-20.html#_4.11
+type FootnoteLink struct {
+	// Role is the xlink:role of the extended link.
+	Role string `xml:"role,attr"`
+
+	Locators  []Locator     `xml:"loc"`
+	Footnotes []Footnote    `xml:"footnote"`
+	Arcs      []FootnoteArc `xml:"footnoteArc"`
+}
+
+// Locator points to a fact that a footnote is attached to.
+// https://www.xbrl.org/Specification/XBRL-2.1/REC-2003-12-31/XBRL-2.1-REC-2003-12-31+corrected-errata-2013-02-20.html#_4.11.1.1
+type Locator struct {
+	// Href is the xlink:href of the locator, usually "#" followed by the ID of the fact.
+	Href string `xml:"href,attr"`
+
+	// Label is the xlink:label that arcs use to refer to this locator. Several locators can share a label.
+	Label string `xml:"label,attr"`
+}
+
+// FactID returns the fragment identifier of the locator's Href, which is the ID of the fact it points to.
+// It returns an empty string if Href has no fragment identifier.
+func (l Locator) FactID() string {
+	if index := strings.IndexRune(l.Href, '#'); index != -1 {
+		return l.Href[index+1:]
+	}
+
+	return ""
+}
+
+// Footnote is a piece of text (which may contain XHTML markup) that adds information to one or more facts.
+// https://www.xbrl.org/Specification/XBRL-2.1/REC-2003-12-31/XBRL-2.1-REC-2003-12-31+corrected-errata-2013-02-20.html#_4.11.1.3
+type Footnote struct {
+	ID string `xml:"id,attr"`
+
+	// Label is the xlink:label that arcs use to refer to this footnote.
+	Label string `xml:"label,attr"`
+
+	// Role is the xlink:role of the footnote, usually "http://www.xbrl.org/2003/role/footnote".
+	Role string `xml:"role,attr"`
+
+	// Lang is the xml:lang attribute of the footnote, ie "en-US".
+	Lang string `xml:"lang,attr"`
+
+	// Content is the raw content of the footnote, including any XHTML markup.
+	Content string `xml:",innerxml"`
+}
+
+// FootnoteArc connects locators to footnotes (or other locators) by their labels.
+// https://www.xbrl.org/Specification/XBRL-2.1/REC-2003-12-31/XBRL-2.1-REC-2003-12-31+corrected-errata-2013-02-20.html#_4.11.1.2
+type FootnoteArc struct {
+	From    string `xml:"from,attr"`
+	To      string `xml:"to,attr"`
+	ArcRole string `xml:"arcrole,attr"`
+	Order   string `xml:"order,attr"`
+}
+
+// footnotesByFactID follows the fact-footnote arcs of every FootnoteLink from the facts' locators to their footnotes.
+// Arcs with a custom arcrole (see ArcRoleRef) aren't followed, since they can mean anything but "this footnote is about that fact".
+// The footnotes of each fact are in the order of the arcs that reference them.
+func footnotesByFactID(links []FootnoteLink) map[string][]Footnote {
+	footnotes := make(map[string][]Footnote)
+	for _, link := range links {
+		factIDsByLabel := make(map[string][]string, len(link.Locators))
+		for _, locator := range link.Locators {
+			if factID := locator.FactID(); factID != "" {
+				factIDsByLabel[locator.Label] = append(factIDsByLabel[locator.Label], factID)
+			}
+		}
+
+		footnotesByLabel := make(map[string][]Footnote, len(link.Footnotes))
+		for _, footnote := range link.Footnotes {
+			footnotesByLabel[footnote.Label] = append(footnotesByLabel[footnote.Label], footnote)
+		}
+
+		for _, arc := range link.Arcs {
+			if arc.ArcRole != ArcRoleFactFootnote {
+				continue
+			}
+
+			for _, factID := range factIDsByLabel[arc.From] {
+				footnotes[factID] = append(footnotes[factID], footnotesByLabel[arc.To]...)
+			}
+		}
+	}
+
+	return footnotes
+}
@@ -0,0 +1,75 @@
+package xbrl
+
+import (
+	"encoding/xml"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestUnmarshalFootnotes(t *testing.T) {
+	// language=xml
+	xbrlXML := `<xbrl xmlns="http://www.xbrl.org/2003/instance" xmlns:link="http://www.xbrl.org/2003/linkbase" xmlns:xlink="http://www.w3.org/1999/xlink" xmlns:us-gaap="http://fasb.org/us-gaap/2020-01-31">
+    <context id="c1">
+        <entity>
+            <identifier scheme="http://www.sec.gov/CIK">0000320193</identifier>
+        </entity>
+        <period>
+            <instant>2021-03-27</instant>
+        </period>
+    </context>
+    <unit id="usd">
+        <measure>iso4217:USD</measure>
+    </unit>
+
+    <us-gaap:Revenues id="f-1" contextRef="c1" unitRef="usd" decimals="0">100</us-gaap:Revenues>
+    <us-gaap:CostOfRevenue id="f-2" contextRef="c1" unitRef="usd" decimals="0">60</us-gaap:CostOfRevenue>
+    <us-gaap:GrossProfit contextRef="c1" unitRef="usd" decimals="0">40</us-gaap:GrossProfit>
+
+    <link:footnoteLink xlink:type="extended" xlink:role="http://www.xbrl.org/2003/role/link">
+        <link:loc xlink:type="locator" xlink:href="#f-1" xlink:label="fact1"/>
+        <link:loc xlink:type="locator" xlink:href="#f-2" xlink:label="fact2"/>
+        <link:footnote xlink:type="resource" xlink:label="footnote1" xlink:role="http://www.xbrl.org/2003/role/footnote" xml:lang="en-US" id="fn-1">Includes <b>discontinued</b> operations.</link:footnote>
+        <link:footnote xlink:type="resource" xlink:label="footnote2" xlink:role="http://www.xbrl.org/2003/role/footnote" xml:lang="fr">Hors taxes.</link:footnote>
+        <link:footnoteArc xlink:type="arc" xlink:from="fact1" xlink:to="footnote1" xlink:arcrole="http://www.xbrl.org/2003/arcrole/fact-footnote" order="1"/>
+        <link:footnoteArc xlink:type="arc" xlink:from="fact1" xlink:to="footnote2" xlink:arcrole="http://www.xbrl.org/2003/arcrole/fact-footnote" order="2"/>
+        <link:footnoteArc xlink:type="arc" xlink:from="fact2" xlink:to="footnote2" xlink:arcrole="http://www.xbrl.org/2003/arcrole/fact-footnote"/>
+        <link:footnoteArc xlink:type="arc" xlink:from="fact2" xlink:to="footnote1" xlink:arcrole="http://example.com/arcrole/fact-explanatoryFact"/>
+    </link:footnoteLink>
+</xbrl>`
+
+	var raw RawXBRL
+	require.NoError(t, xml.Unmarshal([]byte(xbrlXML), &raw))
+	assert.Len(t, raw.Facts, 3)
+	require.Len(t, raw.FootnoteLinks, 1)
+
+	link := raw.FootnoteLinks[0]
+	assert.Equal(t, "http://www.xbrl.org/2003/role/link", link.Role)
+	assert.Equal(t, []Locator{{Href: "#f-1", Label: "fact1"}, {Href: "#f-2", Label: "fact2"}}, link.Locators)
+	require.Len(t, link.Footnotes, 2)
+	assert.Equal(t, Footnote{
+		ID:      "fn-1",
+		Label:   "footnote1",
+		Role:    "http://www.xbrl.org/2003/role/footnote",
+		Lang:    "en-US",
+		Content: "Includes <b>discontinued</b> operations.",
+	}, link.Footnotes[0])
+	require.Len(t, link.Arcs, 4)
+	assert.Equal(t, FootnoteArc{From: "fact1", To: "footnote1", ArcRole: ArcRoleFactFootnote, Order: "1"}, link.Arcs[0])
+
+	content := NewProcessedXBRL(raw)
+	require.NoError(t, content.Validate())
+
+	revenueFootnotes := content.Footnotes(content.Facts[0])
+	require.Len(t, revenueFootnotes, 2)
+	assert.Equal(t, "en-US", revenueFootnotes[0].Lang)
+	assert.Equal(t, "Hors taxes.", revenueFootnotes[1].Content)
+
+	// The arc with a custom arcrole isn't followed.
+	costFootnotes := content.Footnotes(content.Facts[1])
+	require.Len(t, costFootnotes, 1)
+	assert.Equal(t, "fr", costFootnotes[0].Lang)
+
+	assert.Empty(t, content.Footnotes(content.Facts[2]))
+}
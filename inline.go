@@ -0,0 +1,755 @@
+package xbrl
+
+import (
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Namespaces used by Inline XBRL documents.
+const (
+	// NamespaceInlineXBRL is the namespace of Inline XBRL 1.1 elements (ix:nonFraction, ix:header, etc.).
+	NamespaceInlineXBRL = "http://www.xbrl.org/2013/inlineXBRL"
+	// NamespaceInlineXBRL10 is the namespace of Inline XBRL 1.0 elements, still found in older filings.
+	NamespaceInlineXBRL10 = "http://www.xbrl.org/2008/inlineXBRL"
+
+	// NamespaceXBRLI is the namespace of XBRL 2.1 instance elements (xbrli:context, xbrli:unit, etc.).
+	NamespaceXBRLI = "http://www.xbrl.org/2003/instance"
+	// NamespaceLink is the namespace of XBRL 2.1 linkbase elements (link:schemaRef, link:footnoteLink, etc.).
+	NamespaceLink = "http://www.xbrl.org/2003/linkbase"
+	// NamespaceXSI is the XML Schema instance namespace, which defines the xsi:nil attribute.
+	NamespaceXSI = "http://www.w3.org/2001/XMLSchema-instance"
+
+	// namespaceXML is the namespace bound to the xml prefix, which defines attributes like xml:lang.
+	namespaceXML = "http://www.w3.org/XML/1998/namespace"
+)
+
+// InlineXBRL is an Inline XBRL (iXBRL) document: an XHTML document with XBRL facts embedded in its markup.
+// https://www.xbrl.org/specification/inlinexbrl-part1/rec-2013-11-18/inlinexbrl-part1-rec-2013-11-18.html
+//
+// Unmarshal the root <html> element of an iXBRL document into this struct.
+// The facts (ix:nonFraction, ix:nonNumeric and ix:fraction) and the contexts and units inside ix:header/ix:resources
+// are collected into the embedded XBRL struct, so an inline document can be used exactly like a plain instance document.
+type InlineXBRL struct {
+	XBRL
+
+	// Transforms are used to convert the displayed values of facts with a format attribute into XBRL lexical values.
+	// If Transforms is nil when unmarshalling, the formats in DefaultTransforms() are used.
+	Transforms TransformRegistry
+}
+
+// UnmarshalXML implements xml.Unmarshaler. It walks the whole XHTML document and collects the XBRL it contains.
+func (x *InlineXBRL) UnmarshalXML(d *xml.Decoder, start xml.StartElement) error {
+	p := inlineParser{decoder: d, transforms: x.Transforms, strs: newStringInterner()}
+	if p.transforms == nil {
+		p.transforms = defaultTransforms
+	}
+
+	if err := p.parse(start); err != nil {
+		return err
+	}
+
+	x.XBRL = NewProcessedXBRL(p.raw)
+	return nil
+}
+
+// InlineAttributes holds the attributes of a Fact that only exist in Inline XBRL documents.
+// ValueStr, Numerator and Denominator (and their string forms) of an inline Fact hold the normalized value, after its format, scale and sign were applied,
+// so they can be used exactly like the values of facts read from an XBRL instance document.
+type InlineAttributes struct {
+	// DisplayValue is the content of the fact as it is displayed in the document.
+	// For fractions, it's the text of the whole ix:fraction element.
+	DisplayValue string
+
+	// Format is the expanded name of the transformation applied to DisplayValue, or nil if the fact has no format attribute.
+	Format *xml.Name
+
+	// Scale is the power of 10 that the displayed value was multiplied by, ie 6 for a value displayed in millions.
+	Scale int
+
+	// Sign is "-" if the value of the fact is the negation of the displayed value, otherwise it's empty.
+	Sign string
+
+	// Escape is true for ix:nonNumeric facts whose value keeps the XHTML markup of their content (escape="true").
+	Escape bool
+
+	// ContinuedAt is the ID of the ix:continuation that the content of an ix:nonNumeric fact continues in, if any.
+	// The content of the whole continuation chain is included in DisplayValue and the fact's ValueStr.
+	ContinuedAt string
+}
+
+// isReference returns true if local is the name of one of the elements that reference the taxonomy of a document.
+func isReference(local string) bool {
+	return local == "schemaRef" || local == "linkbaseRef" || local == "roleRef" || local == "arcroleRef"
+}
+
+// isInlineNamespace returns true if space is one of the Inline XBRL namespaces.
+func isInlineNamespace(space string) bool {
+	return space == NamespaceInlineXBRL || space == NamespaceInlineXBRL10
+}
+
+// inlineElement is an open element in the XHTML document being walked by an inlineParser.
+type inlineElement struct {
+	name xml.Name
+
+	// namespaces are the namespace prefixes declared on this element.
+	namespaces map[string]string
+
+	// fact is non-nil when this element is an ix fact or continuation whose content is being collected.
+	fact *inlineFact
+
+	// exclude is true for ix:exclude elements, whose content is not part of the facts they're in.
+	exclude bool
+}
+
+// inlineFact collects the content of an ix element that produces (part of) a fact.
+type inlineFact struct {
+	start xml.StartElement
+	text  strings.Builder
+
+	// markup collects the content including its XHTML markup. It's only used for facts with escape="true" and for continuations,
+	// which don't know whether they are escaped until the fact they continue is found.
+	markup *strings.Builder
+	// tagOpen is true when the last start tag written to markup hasn't been closed with '>' yet, so empty elements can be self-closing.
+	tagOpen bool
+	// escape is true for facts with escape="true".
+	escape bool
+
+	// pos is where the ix element is in the document.
+	pos Position
+
+	// index is the position of the fact in inlineParser.raw.Facts, reserved when the element starts so facts stay in document order.
+	index int
+
+	// fraction is the enclosing ix:fraction for ix:numerator and ix:denominator elements.
+	fraction *inlineFact
+	// numerator and denominator are set on an ix:fraction when its ix:numerator and ix:denominator children end.
+	numerator, denominator *string
+}
+
+// inlineParser walks the tokens of an Inline XBRL document.
+type inlineParser struct {
+	decoder    *xml.Decoder
+	transforms TransformRegistry
+	raw        RawXBRL
+	strs       *stringInterner
+
+	stack []inlineElement
+
+	// pos is the position of the last token that was read.
+	pos Position
+
+	// continuations are the ix:continuation elements in the document by ID.
+	continuations map[string]*inlineFact
+	// continued are the facts with a continuedAt attribute, whose values are set once the whole document was read.
+	continued []*inlineFact
+}
+
+func (p *inlineParser) parse(start xml.StartElement) error {
+	if err := p.startElement(start); err != nil {
+		return err
+	}
+
+	for len(p.stack) > 0 {
+		p.pos = decoderPosition(p.decoder)
+		token, err := p.decoder.Token()
+		if err != nil {
+			return err
+		}
+
+		switch t := token.(type) {
+		case xml.StartElement:
+			if err := p.startElement(t); err != nil {
+				return err
+			}
+		case xml.EndElement:
+			if err := p.endElement(); err != nil {
+				return err
+			}
+		case xml.CharData:
+			p.eachCollectingFact(func(fact *inlineFact) {
+				fact.writeText(t)
+			})
+		}
+	}
+
+	return p.resolveContinuations()
+}
+
+// eachCollectingFact calls fn for each open fact whose content includes the current position in the document,
+// which are all the open facts except the ones outside of an open ix:exclude.
+func (p *inlineParser) eachCollectingFact(fn func(fact *inlineFact)) {
+	first := 0
+	for i := len(p.stack) - 1; i >= 0; i-- {
+		if p.stack[i].exclude {
+			first = i + 1
+			break
+		}
+	}
+
+	for _, element := range p.stack[first:] {
+		if element.fact != nil {
+			fn(element.fact)
+		}
+	}
+}
+
+// startElement handles the start of an element. Contexts and units are decoded in full, everything else is pushed onto the stack.
+func (p *inlineParser) startElement(start xml.StartElement) error {
+	element := inlineElement{name: start.Name, namespaces: declaredNamespaces(start)}
+
+	switch {
+	case start.Name.Space == NamespaceXBRLI && start.Name.Local == "context":
+		var context Context
+		if err := p.decoder.DecodeElement(&context, &start); err != nil {
+			return fmt.Errorf("context at %s: %w", p.pos, err)
+		}
+
+		context.Position = p.pos
+		context.resolveNames(p.scope().with(start))
+		p.raw.Contexts = append(p.raw.Contexts, context)
+		return nil
+	case start.Name.Space == NamespaceXBRLI && start.Name.Local == "unit":
+		var unit Unit
+		if err := p.decoder.DecodeElement(&unit, &start); err != nil {
+			return fmt.Errorf("unit at %s: %w", p.pos, err)
+		}
+
+		unit.Position = p.pos
+		unit.resolveNames(p.scope().with(start))
+		p.raw.Units = append(p.raw.Units, unit)
+		return nil
+	case start.Name.Space == NamespaceLink && isReference(start.Name.Local):
+		// The schemaRef and linkbaseRef elements in ix:references are decoded exactly like in an instance document.
+		return p.raw.decodeElement(p.decoder, start, p.pos, p.scope(), p.strs)
+	case isInlineNamespace(start.Name.Space):
+		switch start.Name.Local {
+		case "nonFraction", "fraction":
+			element.fact = &inlineFact{start: start, pos: p.pos, index: len(p.raw.Facts)}
+			p.raw.Facts = append(p.raw.Facts, Fact{})
+		case "nonNumeric":
+			escape, err := inlineEscape(start)
+			if err != nil {
+				return fmt.Errorf("ix:nonNumeric (%s): %w", inlineAttr(start, "name"), err)
+			}
+
+			element.fact = &inlineFact{start: start, pos: p.pos, index: len(p.raw.Facts), escape: escape}
+			if escape {
+				element.fact.markup = &strings.Builder{}
+			}
+
+			p.raw.Facts = append(p.raw.Facts, Fact{})
+		case "numerator", "denominator":
+			element.fact = &inlineFact{start: start, fraction: p.enclosingFraction()}
+			if element.fact.fraction == nil {
+				return fmt.Errorf("ix:%s is not inside an ix:fraction", start.Name.Local)
+			}
+		case "continuation":
+			id := inlineAttr(start, "id")
+			if id == "" {
+				return errors.New("ix:continuation has no id")
+			}
+
+			if _, exists := p.continuations[id]; exists {
+				return fmt.Errorf("duplicate ix:continuation id: %s", id)
+			}
+
+			element.fact = &inlineFact{start: start, markup: &strings.Builder{}}
+			if p.continuations == nil {
+				p.continuations = make(map[string]*inlineFact)
+			}
+
+			p.continuations[id] = element.fact
+		case "exclude":
+			element.exclude = true
+		}
+	default:
+		p.eachCollectingFact(func(fact *inlineFact) {
+			fact.writeStartTag(start)
+		})
+	}
+
+	p.stack = append(p.stack, element)
+	return nil
+}
+
+// endElement pops the innermost open element and finishes the fact it was collecting, if any.
+func (p *inlineParser) endElement() error {
+	element := p.stack[len(p.stack)-1]
+	defer func() {
+		p.stack = p.stack[:len(p.stack)-1]
+	}()
+
+	if !isInlineNamespace(element.name.Space) {
+		p.eachCollectingFact(func(fact *inlineFact) {
+			fact.writeEndTag(element.name)
+		})
+	}
+
+	if element.fact == nil {
+		return nil
+	}
+
+	switch element.fact.start.Name.Local {
+	case "numerator", "denominator":
+		return p.finishFractionTerm(element.fact)
+	case "continuation":
+		return nil
+	default:
+		if err := p.finishFact(element.fact); err != nil {
+			return fmt.Errorf("fact at %s: %w", element.fact.pos, err)
+		}
+
+		return nil
+	}
+}
+
+// enclosingFraction returns the innermost open ix:fraction, or nil if there is none.
+func (p *inlineParser) enclosingFraction() *inlineFact {
+	for i := len(p.stack) - 1; i >= 0; i-- {
+		if fact := p.stack[i].fact; fact != nil && fact.start.Name.Local == "fraction" {
+			return fact
+		}
+	}
+
+	return nil
+}
+
+// finishFractionTerm parses the content of an ix:numerator or ix:denominator and stores it on the enclosing ix:fraction.
+func (p *inlineParser) finishFractionTerm(term *inlineFact) error {
+	attributes, err := p.inlineAttributes(term)
+	if err != nil {
+		return fmt.Errorf("ix:%s: %w", term.start.Name.Local, err)
+	}
+
+	text, err := p.numericValue(attributes)
+	if err != nil {
+		return fmt.Errorf("ix:%s: %w", term.start.Name.Local, err)
+	}
+
+	if _, err := ParseXSDDecimal(text); err != nil {
+		return fmt.Errorf("invalid ix:%s value: %w", term.start.Name.Local, err)
+	}
+
+	if term.start.Name.Local == "numerator" {
+		term.fraction.numerator = &text
+	} else {
+		term.fraction.denominator = &text
+	}
+
+	return nil
+}
+
+// finishFact builds a Fact from a completed ix:nonFraction, ix:nonNumeric or ix:fraction element.
+func (p *inlineParser) finishFact(inline *inlineFact) error {
+	name, err := p.resolveName(inlineAttr(inline.start, "name"))
+	if err != nil {
+		return fmt.Errorf("ix:%s: %w", inline.start.Name.Local, err)
+	}
+
+	fact := Fact{
+		XMLName:    name.ExpandedName(),
+		Name:       name,
+		ID:         inlineAttr(inline.start, "id"),
+		ContextRef: *p.internAttr(inline.start, "contextRef"),
+		UnitRef:    p.internAttr(inline.start, "unitRef"),
+		Precision:  p.internAttr(inline.start, "precision"),
+		Decimals:   p.internAttr(inline.start, "decimals"),
+		Lang:       p.scope().lang(),
+		Position:   inline.pos,
+	}
+
+	attributes, err := p.inlineAttributes(inline)
+	if err != nil {
+		return fmt.Errorf("ix:%s (%s): %w", inline.start.Name.Local, name.Local, err)
+	}
+
+	fact.Inline = &attributes
+
+	if isNil, err := inlineNil(inline.start); err != nil {
+		return fmt.Errorf("ix:%s (%s): %w", inline.start.Name.Local, name.Local, err)
+	} else if isNil {
+		fact.Nil = sharedBool(isNil)
+		p.raw.Facts[inline.index] = fact
+		return nil
+	}
+
+	switch inline.start.Name.Local {
+	case "fraction":
+		numerator := inline.numerator
+		if numerator != nil && attributes.Sign == "-" {
+			negated := negateDecimalString(*numerator)
+			numerator = &negated
+		}
+
+		if err := fact.setFractionTerms(numerator, inline.denominator); err != nil {
+			return fmt.Errorf("ix:%s (%s): %w", inline.start.Name.Local, name.Local, err)
+		}
+	case "nonFraction":
+		value, err := p.numericValue(attributes)
+		if err != nil {
+			return fmt.Errorf("ix:%s (%s): %w", inline.start.Name.Local, name.Local, err)
+		}
+
+		if attributes.Sign == "-" {
+			value = negateDecimalString(value)
+		}
+
+		fact.ValueStr = p.strs.value(value)
+	default:
+		// The value of a continued fact is set once its continuations have been read.
+		if attributes.ContinuedAt != "" {
+			p.continued = append(p.continued, inline)
+			break
+		}
+
+		if err := p.setNonNumericValue(&fact); err != nil {
+			return err
+		}
+	}
+
+	p.raw.Facts[inline.index] = fact
+	return nil
+}
+
+// setNonNumericValue applies the format of an ix:nonNumeric fact to its displayed value and stores the result in its ValueStr.
+func (p *inlineParser) setNonNumericValue(fact *Fact) error {
+	value := fact.Inline.DisplayValue
+	if fact.Inline.Format != nil {
+		var err error
+		if value, err = p.transforms.Transform(*fact.Inline.Format, value); err != nil {
+			return fmt.Errorf("ix:nonNumeric (%s): %w", fact.XMLName.Local, err)
+		}
+	}
+
+	fact.ValueStr = p.strs.value(value)
+	return nil
+}
+
+// resolveContinuations appends the content of their continuation chains to the facts with a continuedAt attribute.
+func (p *inlineParser) resolveContinuations() error {
+	// used tracks the continuations that are already part of a chain, since each continuation can only continue one fact.
+	used := make(map[string]bool)
+
+	for _, inline := range p.continued {
+		fact := &p.raw.Facts[inline.index]
+		if fact.Inline == nil || fact.Nil != nil {
+			continue
+		}
+
+		var content strings.Builder
+		content.WriteString(fact.Inline.DisplayValue)
+
+		for id := fact.Inline.ContinuedAt; id != ""; {
+			if used[id] {
+				return fmt.Errorf("ix:nonNumeric (%s): ix:continuation %s is part of a cycle or continues more than one fact", fact.XMLName.Local, id)
+			}
+
+			continuation, exists := p.continuations[id]
+			if !exists {
+				return fmt.Errorf("ix:nonNumeric (%s): continuedAt references non-existent ix:continuation: %s", fact.XMLName.Local, id)
+			}
+
+			used[id] = true
+			content.WriteString(continuation.content(inline.escape))
+			id = inlineAttr(continuation.start, "continuedAt")
+		}
+
+		fact.Inline.DisplayValue = content.String()
+		if err := p.setNonNumericValue(fact); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// inlineAttributes reads the InlineAttributes of an ix fact element.
+func (p *inlineParser) inlineAttributes(inline *inlineFact) (InlineAttributes, error) {
+	attributes := InlineAttributes{
+		DisplayValue: inline.content(inline.escape),
+		Sign:         inlineAttr(inline.start, "sign"),
+		Escape:       inline.escape,
+		ContinuedAt:  inlineAttr(inline.start, "continuedAt"),
+	}
+
+	if attributes.Sign != "" && attributes.Sign != "-" {
+		return InlineAttributes{}, fmt.Errorf("invalid sign: %q", attributes.Sign)
+	}
+
+	if format := inlineAttrPtr(inline.start, "format"); format != nil {
+		name, err := p.resolveName(*format)
+		if err != nil {
+			return InlineAttributes{}, fmt.Errorf("format: %w", err)
+		}
+
+		format := name.ExpandedName()
+		attributes.Format = &format
+	}
+
+	scale, err := inlineScale(inline.start)
+	if err != nil {
+		return InlineAttributes{}, err
+	}
+
+	attributes.Scale = scale
+	return attributes, nil
+}
+
+// numericValue applies the format and scale of an ix:nonFraction, ix:numerator or ix:denominator to its displayed value.
+// Without a format, the displayed value only has its surrounding whitespace removed.
+func (p *inlineParser) numericValue(attributes InlineAttributes) (string, error) {
+	value := strings.TrimSpace(attributes.DisplayValue)
+	if attributes.Format != nil {
+		var err error
+		if value, err = p.transforms.Transform(*attributes.Format, attributes.DisplayValue); err != nil {
+			return "", err
+		}
+	}
+
+	if attributes.Scale == 0 {
+		return value, nil
+	}
+
+	return scaleDecimalString(value, attributes.Scale)
+}
+
+// resolveName resolves a prefixed name (ie "us-gaap:Revenues") against the namespaces declared on the open elements.
+func (p *inlineParser) resolveName(prefixed string) (QName, error) {
+	name := ParseQName(prefixed)
+	if name.Local == "" {
+		return QName{}, fmt.Errorf("invalid name: %q", prefixed)
+	}
+
+	space, exists := p.scope().lookup(name.Prefix)
+	if !exists && name.Prefix != "" {
+		return QName{}, fmt.Errorf("undeclared namespace prefix in name: %q", prefixed)
+	}
+
+	name.Space = space
+	return name, nil
+}
+
+// scope returns the namespaces declared on the open elements.
+func (p *inlineParser) scope() namespaceScope {
+	scope := make(namespaceScope, 0, len(p.stack))
+	for _, element := range p.stack {
+		if element.namespaces != nil {
+			scope = append(scope, element.namespaces)
+		}
+	}
+
+	return scope
+}
+
+// internAttr is like inlineAttrPtr, but the value is interned. Missing attributes are returned as nil,
+// except for contextRef, which is returned as an empty string so it can be dereferenced.
+func (p *inlineParser) internAttr(start xml.StartElement, local string) *string {
+	value := inlineAttrPtr(start, local)
+	if value == nil {
+		if local == "contextRef" {
+			return p.strs.intern("")
+		}
+
+		return nil
+	}
+
+	return p.strs.intern(*value)
+}
+
+// inlineAttr returns the value of an unqualified attribute of an ix element, or empty string if it doesn't exist.
+func inlineAttr(start xml.StartElement, local string) string {
+	if value := inlineAttrPtr(start, local); value != nil {
+		return *value
+	}
+
+	return ""
+}
+
+// inlineAttrPtr returns the value of an unqualified attribute of an ix element, or nil if it doesn't exist.
+func inlineAttrPtr(start xml.StartElement, local string) *string {
+	for _, attr := range start.Attr {
+		if attr.Name.Space == "" && attr.Name.Local == local {
+			value := attr.Value
+			return &value
+		}
+	}
+
+	return nil
+}
+
+// inlineScale returns the value of the scale attribute of an ix element, or 0 if it doesn't exist.
+func inlineScale(start xml.StartElement) (int, error) {
+	scale := inlineAttrPtr(start, "scale")
+	if scale == nil {
+		return 0, nil
+	}
+
+	value, err := strconv.Atoi(strings.TrimSpace(*scale))
+	if err != nil {
+		return 0, fmt.Errorf("invalid scale: %q", *scale)
+	}
+
+	return value, nil
+}
+
+// scaleDecimalString multiplies a decimal number by 10 to the power of scale by moving its decimal point.
+// Moving the decimal point instead of multiplying keeps the result exact, ie "0.07" with a scale of 2 is "7" instead of "7.000000000000001".
+func scaleDecimalString(value string, scale int) (string, error) {
+	sign := ""
+	if strings.HasPrefix(value, "-") {
+		sign, value = "-", value[1:]
+	}
+
+	integer, fraction := value, ""
+	if index := strings.IndexRune(value, '.'); index != -1 {
+		integer, fraction = value[:index], value[index+1:]
+	}
+
+	digits := integer + fraction
+	if digits == "" || strings.Trim(digits, "0123456789") != "" {
+		return "", fmt.Errorf("cannot scale non-decimal value: %q", sign+value)
+	}
+
+	// point is the position of the decimal point in digits after scaling.
+	point := len(integer) + scale
+	switch {
+	case point <= 0:
+		digits = strings.Repeat("0", 1-point) + digits
+		point = 1
+	case point > len(digits):
+		digits += strings.Repeat("0", point-len(digits))
+	}
+
+	integer = strings.TrimLeft(digits[:point], "0")
+	fraction = strings.TrimRight(digits[point:], "0")
+
+	if integer == "" {
+		integer = "0"
+	}
+
+	if fraction == "" {
+		if integer == "0" {
+			return "0", nil
+		}
+
+		return sign + integer, nil
+	}
+
+	return sign + integer + "." + fraction, nil
+}
+
+// negateDecimalString returns the negation of a decimal number (or the special values INF and -INF).
+func negateDecimalString(value string) string {
+	if strings.HasPrefix(value, "-") {
+		return value[1:]
+	}
+
+	if strings.Trim(value, "0.") == "" || value == "NaN" {
+		return value
+	}
+
+	return "-" + value
+}
+
+// inlineEscape returns the value of the escape attribute of an ix:nonNumeric element.
+func inlineEscape(start xml.StartElement) (bool, error) {
+	escape := inlineAttrPtr(start, "escape")
+	if escape == nil {
+		return false, nil
+	}
+
+	value, err := strconv.ParseBool(strings.TrimSpace(*escape))
+	if err != nil {
+		return false, fmt.Errorf("invalid escape: %q", *escape)
+	}
+
+	return value, nil
+}
+
+// inlineNil returns the value of the xsi:nil attribute of an ix element.
+func inlineNil(start xml.StartElement) (bool, error) {
+	for _, attr := range start.Attr {
+		if attr.Name.Space == NamespaceXSI && attr.Name.Local == "nil" {
+			return strconv.ParseBool(strings.TrimSpace(attr.Value))
+		}
+	}
+
+	return false, nil
+}
+
+// content returns the content collected for this element, including its XHTML markup if escape is true.
+func (f *inlineFact) content(escape bool) string {
+	if escape && f.markup != nil {
+		return f.markup.String()
+	}
+
+	return f.text.String()
+}
+
+// escapeMarkupText escapes the text content of escaped XHTML.
+var escapeMarkupText = strings.NewReplacer("&", "&amp;", "<", "&lt;", ">", "&gt;")
+
+// escapeMarkupAttr escapes attribute values of escaped XHTML.
+var escapeMarkupAttr = strings.NewReplacer("&", "&amp;", "<", "&lt;", `"`, "&quot;")
+
+// writeText adds text content to this element.
+func (f *inlineFact) writeText(data []byte) {
+	f.text.Write(data)
+
+	if f.markup != nil {
+		f.closeStartTag()
+		escapeMarkupText.WriteString(f.markup, string(data))
+	}
+}
+
+// writeStartTag adds an XHTML start tag to the markup of this element.
+func (f *inlineFact) writeStartTag(start xml.StartElement) {
+	if f.markup == nil {
+		return
+	}
+
+	f.closeStartTag()
+	f.markup.WriteString("<" + start.Name.Local)
+
+	for _, attr := range start.Attr {
+		name := attr.Name.Local
+		switch {
+		case attr.Name.Space == "xmlns" || (attr.Name.Space == "" && attr.Name.Local == "xmlns"):
+			continue
+		case attr.Name.Space == namespaceXML:
+			name = "xml:" + name
+		}
+
+		f.markup.WriteString(" " + name + `="`)
+		escapeMarkupAttr.WriteString(f.markup, attr.Value)
+		f.markup.WriteString(`"`)
+	}
+
+	f.tagOpen = true
+}
+
+// writeEndTag adds an XHTML end tag to the markup of this element. Elements without content are written as self-closing tags.
+func (f *inlineFact) writeEndTag(name xml.Name) {
+	if f.markup == nil {
+		return
+	}
+
+	if f.tagOpen {
+		f.markup.WriteString("/>")
+		f.tagOpen = false
+		return
+	}
+
+	f.markup.WriteString("</" + name.Local + ">")
+}
+
+// closeStartTag finishes a start tag that was left open by writeStartTag.
+func (f *inlineFact) closeStartTag() {
+	if f.tagOpen {
+		f.markup.WriteString(">")
+		f.tagOpen = false
+	}
+}
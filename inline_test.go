@@ -0,0 +1,242 @@
+package xbrl
+
+import (
+	"encoding/xml"
+	"errors"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestUnmarshalInlineXBRL(t *testing.T) {
+	t.Run("simple inline xbrl happy path", func(t *testing.T) {
+		f, err := os.Open("test_data/simple_inline_xbrl.htm")
+		require.NoError(t, err)
+		defer f.Close()
+
+		var content InlineXBRL
+		require.NoError(t, xml.NewDecoder(f).Decode(&content))
+		require.NoError(t, content.Validate())
+
+		assert.Len(t, content.ContextsByID, 2)
+		assert.Equal(t, PeriodTypeDuration, content.ContextsByID["c1"].Period.Type())
+		assert.Equal(t, "0000320193", content.ContextsByID["c1"].Entity.Identifier.Value)
+		assert.Equal(t, PeriodTypeInstant, content.ContextsByID["c2"].Period.Type())
+
+		assert.Len(t, content.UnitsByID, 3)
+		assert.Equal(t, "USD", content.UnitsByID["usd"].String())
+		assert.Equal(t, "USD / shares", content.UnitsByID["usdPerShare"].String())
+
+		require.Len(t, content.Facts, 10)
+
+		amendment := content.Facts[0]
+		assert.Equal(t, xml.Name{Space: "http://xbrl.sec.gov/dei/2020-01-31", Local: "AmendmentFlag"}, amendment.XMLName)
+		assert.Equal(t, FactTypeNonNumeric, amendment.Type())
+		assert.Equal(t, "false", amendment.Value())
+		assert.Equal(t, "en-US", amendment.Lang, "inherited from the html element")
+
+		documentType := content.Facts[1]
+		assert.Equal(t, xml.Name{Space: "http://xbrl.sec.gov/dei/2020-01-31", Local: "DocumentType"}, documentType.XMLName)
+		assert.Equal(t, "10-Q", documentType.Value())
+
+		periodEnd := content.Facts[2]
+		assert.Equal(t, "DocumentPeriodEndDate", periodEnd.XMLName.Local)
+		assert.Equal(t, "2021-03-27", periodEnd.Value())
+
+		revenues := content.Facts[3]
+		assert.Equal(t, xml.Name{Space: "http://fasb.org/us-gaap/2020-01-31", Local: "Revenues"}, revenues.XMLName)
+		assert.Equal(t, FactTypeNonFraction, revenues.Type())
+		assert.Equal(t, "f-1", revenues.ID)
+		assert.Equal(t, "c1", revenues.ContextRef)
+		require.NotNil(t, revenues.UnitRef)
+		assert.Equal(t, "usd", *revenues.UnitRef)
+		require.NotNil(t, revenues.Decimals)
+		assert.Equal(t, "-6", *revenues.Decimals)
+		assert.Nil(t, revenues.Precision)
+		assert.Equal(t, "89584000000", revenues.Value())
+		require.NotNil(t, revenues.Inline)
+		assert.Equal(t, "89,584", revenues.Inline.DisplayValue)
+		assert.Equal(t, &xml.Name{Space: NamespaceTransformationRegistry4, Local: "num-dot-decimal"}, revenues.Inline.Format)
+		assert.Equal(t, 6, revenues.Inline.Scale)
+		assert.Empty(t, revenues.Inline.Sign)
+
+		nonOperating := content.Facts[4]
+		assert.Equal(t, "-727000000", nonOperating.Value())
+		assert.Equal(t, "727", nonOperating.Inline.DisplayValue)
+		assert.Equal(t, "-", nonOperating.Inline.Sign)
+		val, err := nonOperating.NumericValue()
+		require.NoError(t, err)
+		assert.EqualValues(t, -727000000, val)
+
+		eps := content.Facts[5]
+		val, err = eps.NumericValue()
+		require.NoError(t, err)
+		assert.EqualValues(t, 1.41, val)
+
+		oci := content.Facts[6]
+		assert.Equal(t, FactTypeNil, oci.Type())
+		assert.True(t, oci.IsValid())
+
+		ownership := content.Facts[7]
+		assert.Equal(t, FactTypeFraction, ownership.Type())
+		val, err = ownership.NumericValue()
+		require.NoError(t, err)
+		assert.EqualValues(t, 1.0/3.0, val)
+
+		basisOfAccounting := content.Facts[8]
+		assert.Equal(t, FactTypeNonNumeric, basisOfAccounting.Type())
+		assert.True(t, basisOfAccounting.Inline.Escape)
+		assert.Equal(t, "cont-1", basisOfAccounting.Inline.ContinuedAt)
+		assert.Equal(t, `<p>Basis of <b>Presentation</b></p>
+<p>The condensed consolidated financial statements include the accounts of Apple Inc.</p>`+
+			`<p>Certain prior period amounts<br/> have been reclassified.</p>`+
+			`<p>End of note &amp; more.</p>`, basisOfAccounting.Value())
+
+		fiscalPeriod := content.Facts[9]
+		assert.False(t, fiscalPeriod.Inline.Escape)
+		assert.Equal(t, "The Company's fiscal year is the 52- or 53-week period that ends on the last Saturday of September.", fiscalPeriod.Value())
+	})
+
+	t.Run("continuation cycle", func(t *testing.T) {
+		// language=xml
+		inlineXML := `<html xmlns:ix="http://www.xbrl.org/2013/inlineXBRL" xmlns:us-gaap="http://fasb.org/us-gaap/2020-01-31">
+	<ix:nonNumeric name="us-gaap:TextBlock" contextRef="c1" continuedAt="a">start</ix:nonNumeric>
+	<ix:continuation id="a" continuedAt="b">middle</ix:continuation>
+	<ix:continuation id="b" continuedAt="a">end</ix:continuation>
+</html>`
+
+		var content InlineXBRL
+		assert.Error(t, xml.Unmarshal([]byte(inlineXML), &content))
+	})
+
+	t.Run("dangling continuation", func(t *testing.T) {
+		// language=xml
+		inlineXML := `<html xmlns:ix="http://www.xbrl.org/2013/inlineXBRL" xmlns:us-gaap="http://fasb.org/us-gaap/2020-01-31">
+	<ix:nonNumeric name="us-gaap:TextBlock" contextRef="c1" continuedAt="a">start</ix:nonNumeric>
+	<ix:continuation id="a" continuedAt="missing">middle</ix:continuation>
+</html>`
+
+		var content InlineXBRL
+		assert.Error(t, xml.Unmarshal([]byte(inlineXML), &content))
+	})
+
+	t.Run("displayed value doesn't match format", func(t *testing.T) {
+		// language=xml
+		inlineXML := `<html xmlns:ix="http://www.xbrl.org/2013/inlineXBRL" xmlns:ixt="http://www.xbrl.org/inlineXBRL/transformation/2020-02-12" xmlns:us-gaap="http://fasb.org/us-gaap/2020-01-31">
+	<ix:nonFraction name="us-gaap:Revenues" contextRef="c1" unitRef="usd" decimals="-6" format="ixt:num-dot-decimal">89.584,12</ix:nonFraction>
+</html>`
+
+		var content InlineXBRL
+		err := xml.Unmarshal([]byte(inlineXML), &content)
+
+		var transformErr *TransformError
+		require.True(t, errors.As(err, &transformErr))
+		assert.Equal(t, xml.Name{Space: NamespaceTransformationRegistry4, Local: "num-dot-decimal"}, transformErr.Format)
+		assert.Equal(t, "89.584,12", transformErr.Value)
+	})
+
+	t.Run("custom transform registry", func(t *testing.T) {
+		// language=xml
+		inlineXML := `<html xmlns:ix="http://www.xbrl.org/2013/inlineXBRL" xmlns:my="http://example.com/transforms" xmlns:dei="http://xbrl.sec.gov/dei/2020-01-31">
+	<ix:nonNumeric name="dei:DocumentType" contextRef="c1" format="my:upper">10-q</ix:nonNumeric>
+</html>`
+
+		content := InlineXBRL{Transforms: DefaultTransforms()}
+		content.Transforms.Register("http://example.com/transforms", "upper", func(displayed string) (string, error) {
+			return strings.ToUpper(displayed), nil
+		})
+
+		require.NoError(t, xml.Unmarshal([]byte(inlineXML), &content))
+		require.Len(t, content.Facts, 1)
+		assert.Equal(t, "10-Q", content.Facts[0].Value())
+	})
+
+	t.Run("unknown format", func(t *testing.T) {
+		// language=xml
+		inlineXML := `<html xmlns:ix="http://www.xbrl.org/2013/inlineXBRL" xmlns:my="http://example.com/transforms" xmlns:dei="http://xbrl.sec.gov/dei/2020-01-31">
+	<ix:nonNumeric name="dei:DocumentType" contextRef="c1" format="my:upper">10-q</ix:nonNumeric>
+</html>`
+
+		var content InlineXBRL
+		assert.ErrorIs(t, xml.Unmarshal([]byte(inlineXML), &content), ErrUnknownTransform)
+	})
+
+	t.Run("scale and sign on a fraction", func(t *testing.T) {
+		// language=xml
+		inlineXML := `<html xmlns:ix="http://www.xbrl.org/2013/inlineXBRL" xmlns:us-gaap="http://fasb.org/us-gaap/2020-01-31">
+	<ix:fraction name="us-gaap:Ratio" contextRef="c1" unitRef="pure" sign="-">
+		<ix:numerator scale="-2">50</ix:numerator> / <ix:denominator>3</ix:denominator>
+	</ix:fraction>
+</html>`
+
+		var content InlineXBRL
+		require.NoError(t, xml.Unmarshal([]byte(inlineXML), &content))
+		require.Len(t, content.Facts, 1)
+
+		val, err := content.Facts[0].NumericValue()
+		require.NoError(t, err)
+		assert.InDelta(t, -0.5/3, val, 1e-12)
+
+		rat, err := content.Facts[0].RatValue()
+		require.NoError(t, err)
+		assert.Equal(t, "-1/6", rat.String())
+	})
+
+	t.Run("invalid scale", func(t *testing.T) {
+		// language=xml
+		inlineXML := `<html xmlns:ix="http://www.xbrl.org/2013/inlineXBRL" xmlns:us-gaap="http://fasb.org/us-gaap/2020-01-31">
+	<ix:nonFraction name="us-gaap:Revenues" contextRef="c1" unitRef="usd" decimals="-6" scale="millions">727</ix:nonFraction>
+</html>`
+
+		var content InlineXBRL
+		assert.Error(t, xml.Unmarshal([]byte(inlineXML), &content))
+	})
+
+	t.Run("undeclared prefix in fact name", func(t *testing.T) {
+		// language=xml
+		inlineXML := `<html xmlns:ix="http://www.xbrl.org/2013/inlineXBRL">
+	<ix:nonNumeric name="nope:Thing" contextRef="c1">text</ix:nonNumeric>
+</html>`
+
+		var content InlineXBRL
+		assert.Error(t, xml.Unmarshal([]byte(inlineXML), &content))
+	})
+
+	t.Run("numerator outside of fraction", func(t *testing.T) {
+		// language=xml
+		inlineXML := `<html xmlns:ix="http://www.xbrl.org/2013/inlineXBRL">
+	<ix:numerator>1</ix:numerator>
+</html>`
+
+		var content InlineXBRL
+		assert.Error(t, xml.Unmarshal([]byte(inlineXML), &content))
+	})
+}
+
+func TestScaleDecimalString(t *testing.T) {
+	tests := []struct {
+		value    string
+		scale    int
+		expected string
+	}{
+		{"727", 6, "727000000"},
+		{"0.07", 2, "7"},
+		{"1.41", 1, "14.1"},
+		{"1.41", -2, "0.0141"},
+		{"-12.5", -3, "-0.0125"},
+		{"000", 3, "0"},
+		{"89584", -3, "89.584"},
+	}
+
+	for _, tc := range tests {
+		value, err := scaleDecimalString(tc.value, tc.scale)
+		require.NoError(t, err)
+		assert.Equal(t, tc.expected, value, "%s scaled by %d", tc.value, tc.scale)
+	}
+
+	_, err := scaleDecimalString("INF", 3)
+	assert.Error(t, err)
+}
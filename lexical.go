@@ -0,0 +1,102 @@
+package xbrl
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// ErrInvalidLexicalValue is returned (wrapped) when the value of a numeric fact isn't a valid lexical form of its XML Schema type.
+var ErrInvalidLexicalValue = errors.New("invalid lexical value")
+
+type NumericItemType string
+
+// The XML Schema types that numeric item types are derived from, which decide what lexical forms a value can have.
+// XBRL doesn't say what type an item is, that's defined by its concept in the taxonomy schema.
+const (
+	// NumericItemTypeDecimal is for items derived from xsd:decimal (or its subtypes like xsd:integer),
+	// which includes the most common item types: monetaryItemType, sharesItemType, pureItemType and decimalItemType.
+	// Decimal values like "-1.41" or "+727" are allowed, but not exponents, INF or NaN.
+	NumericItemTypeDecimal NumericItemType = "decimal"
+
+	// NumericItemTypeDouble is for items derived from xsd:double (doubleItemType).
+	// It allows everything decimal values allow, as well as exponents ("1.5E3") and the special values INF, -INF and NaN.
+	NumericItemTypeDouble NumericItemType = "double"
+
+	// NumericItemTypeFloat is for items derived from xsd:float (floatItemType).
+	// It has the same lexical forms as NumericItemTypeDouble, but values are rounded to 32 bits.
+	NumericItemTypeFloat NumericItemType = "float"
+)
+
+var (
+	// xsdDecimalPattern is the lexical space of xsd:decimal. https://www.w3.org/TR/xmlschema-2/#decimal
+	xsdDecimalPattern = regexp.MustCompile(`^[+-]?([0-9]+(\.[0-9]*)?|\.[0-9]+)$`)
+
+	// xsdDoublePattern is the lexical space of xsd:double and xsd:float, except for the special values. https://www.w3.org/TR/xmlschema-2/#double
+	xsdDoublePattern = regexp.MustCompile(`^[+-]?([0-9]+(\.[0-9]*)?|\.[0-9]+)([Ee][+-]?[0-9]+)?$`)
+)
+
+// collapseWhitespace applies the XML Schema "collapse" whitespace facet, which all the numeric types use.
+// Only XML whitespace (space, tab, carriage return and line feed) is removed, not other unicode spaces.
+func collapseWhitespace(value string) string {
+	return strings.Join(strings.FieldsFunc(value, func(r rune) bool {
+		return r == ' ' || r == '\t' || r == '\r' || r == '\n'
+	}), " ")
+}
+
+// ParseXSDDecimal parses the lexical form of an xsd:decimal into an exact big.Rat.
+// Surrounding whitespace and a leading '+' are allowed, exponents, INF and NaN are not.
+func ParseXSDDecimal(value string) (*big.Rat, error) {
+	collapsed := collapseWhitespace(value)
+	if !xsdDecimalPattern.MatchString(collapsed) {
+		return nil, fmt.Errorf("%w for xsd:decimal: %q", ErrInvalidLexicalValue, value)
+	}
+
+	rat, ok := new(big.Rat).SetString(strings.TrimPrefix(collapsed, "+"))
+	if !ok {
+		return nil, fmt.Errorf("%w for xsd:decimal: %q", ErrInvalidLexicalValue, value)
+	}
+
+	return rat, nil
+}
+
+// ParseXSDDouble parses the lexical form of an xsd:double (or xsd:float if bitSize is 32) into a float64.
+// On top of decimal values, exponents and the special values "INF", "-INF" and "NaN" are allowed,
+// but not the other forms strconv.ParseFloat accepts, like "Inf", "infinity" or hexadecimal values.
+// Values that are out of range become +/-Inf or 0, as if they were rounded.
+func ParseXSDDouble(value string, bitSize int) (float64, error) {
+	collapsed := collapseWhitespace(value)
+	if collapsed != "INF" && collapsed != "-INF" && collapsed != "NaN" && !xsdDoublePattern.MatchString(collapsed) {
+		return 0, fmt.Errorf("%w for xsd:double: %q", ErrInvalidLexicalValue, value)
+	}
+
+	parsed, err := strconv.ParseFloat(strings.TrimPrefix(collapsed, "+"), bitSize)
+	if err != nil && !errors.Is(err, strconv.ErrRange) {
+		return 0, fmt.Errorf("%w for xsd:double: %q", ErrInvalidLexicalValue, value)
+	}
+
+	return parsed, nil
+}
+
+// parseNumeric parses value according to the lexical rules of itemType.
+func parseNumeric(value string, itemType NumericItemType) (float64, error) {
+	switch itemType {
+	case NumericItemTypeDecimal:
+		rat, err := ParseXSDDecimal(value)
+		if err != nil {
+			return 0, err
+		}
+
+		parsed, _ := rat.Float64()
+		return parsed, nil
+	case NumericItemTypeDouble:
+		return ParseXSDDouble(value, 64)
+	case NumericItemTypeFloat:
+		return ParseXSDDouble(value, 32)
+	default:
+		return 0, fmt.Errorf("unknown numeric item type: %q", itemType)
+	}
+}
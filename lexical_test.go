@@ -0,0 +1,105 @@
+package xbrl
+
+import (
+	"encoding/xml"
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseXSDDecimal(t *testing.T) {
+	valid := map[string]string{
+		"727":           "727",
+		"\n\t 727.5 \n": "1455/2",
+		"+1.41":         "141/100",
+		"-.5":           "-1/2",
+		"5.":            "5",
+		"000.100":       "1/10",
+	}
+
+	for value, expected := range valid {
+		rat, err := ParseXSDDecimal(value)
+		require.NoError(t, err, value)
+		assert.Equal(t, expected, rat.RatString(), value)
+	}
+
+	for _, value := range []string{"", " ", "INF", "NaN", "1e3", "0x1p-2", "1/3", "1 000", "1,000", "--1", "+-1", ".", " 727"} {
+		_, err := ParseXSDDecimal(value)
+		assert.ErrorIs(t, err, ErrInvalidLexicalValue, value)
+	}
+}
+
+func TestParseXSDDouble(t *testing.T) {
+	valid := map[string]float64{
+		" 1.5E3 ": 1500,
+		"+12.":    12,
+		"-.5e-1":  -0.05,
+		"INF":     math.Inf(1),
+		"-INF":    math.Inf(-1),
+		"1e400":   math.Inf(1),
+	}
+
+	for value, expected := range valid {
+		parsed, err := ParseXSDDouble(value, 64)
+		require.NoError(t, err, value)
+		assert.Equal(t, expected, parsed, value)
+	}
+
+	parsed, err := ParseXSDDouble("NaN", 64)
+	require.NoError(t, err)
+	assert.True(t, math.IsNaN(parsed))
+
+	for _, value := range []string{"", "Inf", "inf", "+INF", "infinity", "nan", "0x1p-2", "1e", "e3", "1_000"} {
+		_, err := ParseXSDDouble(value, 64)
+		assert.ErrorIs(t, err, ErrInvalidLexicalValue, value)
+	}
+}
+
+func TestFactNumericValueLexical(t *testing.T) {
+	t.Run("whitespace and plus sign", func(t *testing.T) {
+		fact := Fact{ContextRef: "c1", UnitRef: stringPtr("u1"), Decimals: stringPtr("0"), ValueStr: stringPtr("\n    +727\n")}
+
+		val, err := fact.NumericValue()
+		require.NoError(t, err)
+		assert.EqualValues(t, 727, val)
+	})
+
+	t.Run("INF depends on the item type", func(t *testing.T) {
+		fact := Fact{
+			Name:       QName{Local: "Ratio", Prefix: "my"},
+			ID:         "f-1",
+			ContextRef: "c1",
+			UnitRef:    stringPtr("u1"),
+			Decimals:   stringPtr("INF"),
+			ValueStr:   stringPtr("INF"),
+		}
+
+		_, err := fact.NumericValue()
+		assert.ErrorIs(t, err, ErrInvalidLexicalValue)
+		assert.EqualError(t, err, `fact my:Ratio (id f-1): invalid lexical value for xsd:decimal: "INF"`)
+
+		_, err = fact.RatValue()
+		assert.ErrorIs(t, err, ErrInvalidLexicalValue)
+
+		val, err := fact.NumericValueAs(NumericItemTypeDouble)
+		require.NoError(t, err)
+		assert.True(t, math.IsInf(val, 1))
+	})
+
+	t.Run("float item type", func(t *testing.T) {
+		fact := Fact{ContextRef: "c1", UnitRef: stringPtr("u1"), Decimals: stringPtr("INF"), ValueStr: stringPtr("0.1")}
+
+		val, err := fact.NumericValueAs(NumericItemTypeFloat)
+		require.NoError(t, err)
+		assert.Equal(t, float64(float32(0.1)), val)
+	})
+
+	t.Run("hex float", func(t *testing.T) {
+		fact := Fact{XMLName: xml.Name{Local: "Assets"}, ContextRef: "c1", UnitRef: stringPtr("u1"), Decimals: stringPtr("0"), ValueStr: stringPtr("0x1p-2")}
+
+		_, err := fact.NumericValueAs(NumericItemTypeDouble)
+		assert.EqualError(t, err, `fact Assets: invalid lexical value for xsd:double: "0x1p-2"`)
+	})
+}
@@ -0,0 +1,262 @@
+package xbrl
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"strings"
+	"time"
+)
+
+// ErrMissingPeriodDate is returned (wrapped) when a Period doesn't have the date that was asked for,
+// ie when calling StartTime() on an instant period.
+var ErrMissingPeriodDate = errors.New("period doesn't have this date")
+
+// ErrInvalidPeriod is returned (wrapped) by Period.Validate() when a period breaks the rules of section 4.7.2 of the spec.
+var ErrInvalidPeriod = errors.New("invalid period")
+
+// StartTime returns the start of a duration period.
+// A startDate without a time means the start of that day (00:00:00).
+func (p Period) StartTime() (time.Time, error) {
+	if p.StartDate == nil {
+		return time.Time{}, fmt.Errorf("startDate: %w", ErrMissingPeriodDate)
+	}
+
+	return parsePeriodDate(*p.StartDate, false)
+}
+
+// EndTime returns the end of a duration period.
+// Following section 4.7.2 of the spec, an endDate without a time means the end of that day,
+// which is midnight at the start of the next day. For example, an endDate of 2021-03-27 returns 2021-03-28T00:00:00.
+// https://www.xbrl.org/Specification/XBRL-2.1/REC-2003-12-31/XBRL-2.1-REC-2003-12-31+corrected-errata-2013-02-20.html#_4.7.2
+func (p Period) EndTime() (time.Time, error) {
+	if p.EndDate == nil {
+		return time.Time{}, fmt.Errorf("endDate: %w", ErrMissingPeriodDate)
+	}
+
+	return parsePeriodDate(*p.EndDate, true)
+}
+
+// InstantTime returns the instant of an instant period.
+// Like an endDate, an instant without a time means the end of that day (midnight at the start of the next day).
+func (p Period) InstantTime() (time.Time, error) {
+	if p.Instant == nil {
+		return time.Time{}, fmt.Errorf("instant: %w", ErrMissingPeriodDate)
+	}
+
+	return parsePeriodDate(*p.Instant, true)
+}
+
+// Validate checks the period against section 4.7.2 of the spec:
+// it must be exactly one of an instant, a duration (with both a startDate and an endDate) or forever,
+// its dates must be valid xsd:date or xsd:dateTime values, and the end of a duration must be after its start.
+// Since an endDate without a time is the end of that day, a duration can start and end on the same date.
+// https://www.xbrl.org/Specification/XBRL-2.1/REC-2003-12-31/XBRL-2.1-REC-2003-12-31+corrected-errata-2013-02-20.html#_4.7.2
+func (p Period) Validate() error {
+	kinds := 0
+	if p.Forever != nil {
+		kinds++
+	}
+
+	if p.Instant != nil {
+		kinds++
+	}
+
+	if p.StartDate != nil || p.EndDate != nil {
+		kinds++
+	}
+
+	switch {
+	case kinds == 0:
+		return fmt.Errorf("%w: no instant, duration or forever", ErrInvalidPeriod)
+	case kinds > 1:
+		return fmt.Errorf("%w: more than one of an instant, a duration or forever", ErrInvalidPeriod)
+	case p.Forever != nil:
+		return nil
+	case p.Instant != nil:
+		if _, err := p.InstantTime(); err != nil {
+			return fmt.Errorf("%w: instant: %v", ErrInvalidPeriod, err)
+		}
+
+		return nil
+	case p.StartDate == nil:
+		return fmt.Errorf("%w: duration without a startDate", ErrInvalidPeriod)
+	case p.EndDate == nil:
+		return fmt.Errorf("%w: duration without an endDate", ErrInvalidPeriod)
+	}
+
+	start, err := p.StartTime()
+	if err != nil {
+		return fmt.Errorf("%w: startDate: %v", ErrInvalidPeriod, err)
+	}
+
+	end, err := p.EndTime()
+	if err != nil {
+		return fmt.Errorf("%w: endDate: %v", ErrInvalidPeriod, err)
+	}
+
+	if !end.After(start) {
+		return fmt.Errorf("%w: endDate %s isn't after startDate %s", ErrInvalidPeriod, collapseWhitespace(*p.EndDate), collapseWhitespace(*p.StartDate))
+	}
+
+	return nil
+}
+
+// periodDateLayouts are the layouts of xsd:date and xsd:dateTime values, with and without a timezone.
+// time.Parse accepts fractional seconds after the seconds field even though the layouts don't include them.
+var periodDateLayouts = []struct {
+	layout   string
+	dateOnly bool
+}{
+	{"2006-01-02T15:04:05Z07:00", false},
+	{"2006-01-02T15:04:05", false},
+	{"2006-01-02Z07:00", true},
+	{"2006-01-02", true},
+}
+
+// parsePeriodDate parses the xsd:date or xsd:dateTime value of a period element.
+// If endOfDay is true, a date without a time is the end of that day instead of its start.
+// Values without a timezone are returned in UTC.
+func parsePeriodDate(value string, endOfDay bool) (time.Time, error) {
+	collapsed := collapseWhitespace(value)
+
+	// xsd:dateTime allows 24:00:00, which is the same as 00:00:00 on the next day, but time.Parse doesn't.
+	var nextDay bool
+	if index := strings.Index(collapsed, "T24:00:00"); index != -1 {
+		rest := strings.TrimLeft(collapsed[index+len("T24:00:00"):], ".0")
+		collapsed = collapsed[:index] + "T00:00:00" + rest
+		nextDay = true
+	}
+
+	for _, layout := range periodDateLayouts {
+		parsed, err := time.Parse(layout.layout, collapsed)
+		if err != nil {
+			continue
+		}
+
+		if nextDay || (layout.dateOnly && endOfDay) {
+			parsed = parsed.AddDate(0, 0, 1)
+		}
+
+		return parsed, nil
+	}
+
+	return time.Time{}, fmt.Errorf("invalid period date: %q", value)
+}
+
+// PeriodLength is the length of a duration Period.
+type PeriodLength struct {
+	// Days is the number of days in the period, counting both the start and end date.
+	// For example, a period from 2020-12-27 to 2021-03-27 is 91 days long.
+	Days int
+
+	// Months is the number of months in the period, rounded to the nearest month.
+	// Periods of 13 or 14 weeks are 3 months long, and 52 and 53 week years are 12 months long.
+	Months int
+}
+
+// averageDaysPerMonth is the average length of a month in the Gregorian calendar, used to turn days into months.
+const averageDaysPerMonth = 365.2425 / 12
+
+// Length returns the length of a duration period in days and months.
+func (p Period) Length() (PeriodLength, error) {
+	if p.Type() != PeriodTypeDuration {
+		return PeriodLength{}, fmt.Errorf("period of type %s has no length", p.Type())
+	}
+
+	start, err := p.StartTime()
+	if err != nil {
+		return PeriodLength{}, err
+	}
+
+	end, err := p.EndTime()
+	if err != nil {
+		return PeriodLength{}, err
+	}
+
+	// Rounding takes care of days that aren't 24 hours long because of a timezone's daylight saving time.
+	days := int(math.Round(end.Sub(start).Hours() / 24))
+	return PeriodLength{Days: days, Months: int(math.Round(float64(days) / averageDaysPerMonth))}, nil
+}
+
+type PeriodCategory string
+
+// All the supported PeriodCategory values. See Period.Category() for more information.
+const (
+	// PeriodCategoryQuarter is a period of about 3 months (84 to 98 days), ie a fiscal quarter of 13 or 14 weeks.
+	PeriodCategoryQuarter PeriodCategory = "quarter"
+
+	// PeriodCategoryHalfYear is a period of about 6 months (175 to 189 days), ie the year-to-date period in a second quarter report.
+	PeriodCategoryHalfYear PeriodCategory = "half_year"
+
+	// PeriodCategoryNineMonths is a period of about 9 months (266 to 280 days), ie the year-to-date period in a third quarter report.
+	PeriodCategoryNineMonths PeriodCategory = "nine_months"
+
+	// PeriodCategoryYear is a period of about 12 months (357 to 373 days), ie a fiscal year of 52 or 53 weeks.
+	PeriodCategoryYear PeriodCategory = "year"
+
+	// PeriodCategoryOther is a duration period that doesn't fit any of the other categories.
+	PeriodCategoryOther PeriodCategory = "other"
+)
+
+// periodCategories are the ranges of days (inclusive) of each PeriodCategory.
+// They're wide enough to fit both calendar periods and 52/53 week fiscal calendars, where a quarter can have an extra week.
+var periodCategories = []struct {
+	category         PeriodCategory
+	minDays, maxDays int
+}{
+	{PeriodCategoryQuarter, 84, 98},
+	{PeriodCategoryHalfYear, 175, 189},
+	{PeriodCategoryNineMonths, 266, 280},
+	{PeriodCategoryYear, 357, 373},
+}
+
+// Category classifies a duration period by its length, so facts for a quarter, a year-to-date period or a fiscal year can be told apart.
+// For example, Apple's 2020-09-27 to 2021-03-27 period is 182 days (26 weeks) long, which makes it a PeriodCategoryHalfYear.
+func (p Period) Category() (PeriodCategory, error) {
+	length, err := p.Length()
+	if err != nil {
+		return "", err
+	}
+
+	for _, category := range periodCategories {
+		if length.Days >= category.minDays && length.Days <= category.maxDays {
+			return category.category, nil
+		}
+	}
+
+	return PeriodCategoryOther, nil
+}
+
+// IsStartOf returns true if p is an instant period at the start of the duration period.
+// Because a date-only instant is the end of that day, the instant at the start of a duration starting on 2020-09-27 is 2020-09-26.
+// Opening balances of a period are reported at this instant.
+func (p Period) IsStartOf(duration Period) (bool, error) {
+	instant, err := p.InstantTime()
+	if err != nil {
+		return false, err
+	}
+
+	start, err := duration.StartTime()
+	if err != nil {
+		return false, err
+	}
+
+	return instant.Equal(start), nil
+}
+
+// IsEndOf returns true if p is an instant period at the end of the duration period, ie 2021-03-27 for a duration ending on 2021-03-27.
+// Closing balances of a period are reported at this instant.
+func (p Period) IsEndOf(duration Period) (bool, error) {
+	instant, err := p.InstantTime()
+	if err != nil {
+		return false, err
+	}
+
+	end, err := duration.EndTime()
+	if err != nil {
+		return false, err
+	}
+
+	return instant.Equal(end), nil
+}
@@ -0,0 +1,176 @@
+package xbrl
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPeriodTimes(t *testing.T) {
+	t.Run("date-only duration", func(t *testing.T) {
+		period := Period{StartDate: stringPtr("2020-12-27"), EndDate: stringPtr(" 2021-03-27\n")}
+
+		start, err := period.StartTime()
+		require.NoError(t, err)
+		assert.Equal(t, time.Date(2020, 12, 27, 0, 0, 0, 0, time.UTC), start)
+
+		end, err := period.EndTime()
+		require.NoError(t, err)
+		assert.Equal(t, time.Date(2021, 3, 28, 0, 0, 0, 0, time.UTC), end)
+
+		_, err = period.InstantTime()
+		assert.ErrorIs(t, err, ErrMissingPeriodDate)
+	})
+
+	t.Run("date-only instant is the end of the day", func(t *testing.T) {
+		period := Period{Instant: stringPtr("2021-03-27")}
+
+		instant, err := period.InstantTime()
+		require.NoError(t, err)
+		assert.Equal(t, time.Date(2021, 3, 28, 0, 0, 0, 0, time.UTC), instant)
+
+		// The instant at the end of a period is equal to the end of a duration ending on the same date.
+		end, err := Period{StartDate: stringPtr("2020-12-27"), EndDate: stringPtr("2021-03-27")}.EndTime()
+		require.NoError(t, err)
+		assert.True(t, end.Equal(instant))
+
+		_, err = period.StartTime()
+		assert.ErrorIs(t, err, ErrMissingPeriodDate)
+	})
+
+	t.Run("date-times and timezones", func(t *testing.T) {
+		est := time.FixedZone("", -5*60*60)
+		tests := []struct {
+			value    string
+			expected time.Time
+		}{
+			{"2021-03-27T12:30:00", time.Date(2021, 3, 27, 12, 30, 0, 0, time.UTC)},
+			{"2021-03-27T12:30:00.250Z", time.Date(2021, 3, 27, 12, 30, 0, 250000000, time.UTC)},
+			{"2021-03-27T12:30:00-05:00", time.Date(2021, 3, 27, 12, 30, 0, 0, est)},
+			{"2021-03-27T24:00:00", time.Date(2021, 3, 28, 0, 0, 0, 0, time.UTC)},
+			{"2021-03-27-05:00", time.Date(2021, 3, 28, 0, 0, 0, 0, est)},
+			{"2021-03-27Z", time.Date(2021, 3, 28, 0, 0, 0, 0, time.UTC)},
+		}
+
+		for _, tc := range tests {
+			end, err := Period{EndDate: stringPtr(tc.value)}.EndTime()
+			require.NoError(t, err, tc.value)
+			assert.True(t, tc.expected.Equal(end), "%s: expected %s, got %s", tc.value, tc.expected, end)
+		}
+
+		start, err := Period{StartDate: stringPtr("2021-03-27-05:00")}.StartTime()
+		require.NoError(t, err)
+		assert.True(t, time.Date(2021, 3, 27, 0, 0, 0, 0, est).Equal(start))
+	})
+
+	t.Run("malformed dates", func(t *testing.T) {
+		for _, value := range []string{"", "2021-02-30", "03/27/2021", "2021-3-27", "2021-03-27T25:00:00", "2021-03-27T24:00:01", "2021-03-27 12:00:00"} {
+			_, err := Period{Instant: stringPtr(value)}.InstantTime()
+			assert.Error(t, err, value)
+		}
+	})
+}
+
+func TestPeriodClassification(t *testing.T) {
+	tests := []struct {
+		start, end string
+		length     PeriodLength
+		category   PeriodCategory
+	}{
+		// Apple's 13 week second quarter and 26 week year-to-date periods from the test data.
+		{"2020-12-27", "2021-03-27", PeriodLength{Days: 91, Months: 3}, PeriodCategoryQuarter},
+		{"2020-09-27", "2021-03-27", PeriodLength{Days: 182, Months: 6}, PeriodCategoryHalfYear},
+		// A 14 week quarter and a 53 week year.
+		{"2020-06-28", "2020-10-03", PeriodLength{Days: 98, Months: 3}, PeriodCategoryQuarter},
+		{"2019-09-29", "2020-10-03", PeriodLength{Days: 371, Months: 12}, PeriodCategoryYear},
+		// Calendar periods.
+		{"2021-01-01", "2021-09-30", PeriodLength{Days: 273, Months: 9}, PeriodCategoryNineMonths},
+		{"2020-01-01", "2020-12-31", PeriodLength{Days: 366, Months: 12}, PeriodCategoryYear},
+		{"2021-02-01", "2021-02-28", PeriodLength{Days: 28, Months: 1}, PeriodCategoryOther},
+		{"2021-03-27", "2021-03-27", PeriodLength{Days: 1, Months: 0}, PeriodCategoryOther},
+	}
+
+	for _, tc := range tests {
+		period := Period{StartDate: stringPtr(tc.start), EndDate: stringPtr(tc.end)}
+
+		length, err := period.Length()
+		require.NoError(t, err)
+		assert.Equal(t, tc.length, length, "%s to %s", tc.start, tc.end)
+
+		category, err := period.Category()
+		require.NoError(t, err)
+		assert.Equal(t, tc.category, category, "%s to %s", tc.start, tc.end)
+	}
+
+	t.Run("instant has no length", func(t *testing.T) {
+		_, err := Period{Instant: stringPtr("2021-03-27")}.Category()
+		assert.Error(t, err)
+	})
+
+	t.Run("instants at the start and end of a duration", func(t *testing.T) {
+		duration := Period{StartDate: stringPtr("2020-09-27"), EndDate: stringPtr("2021-03-27")}
+
+		tests := []struct {
+			instant    string
+			start, end bool
+		}{
+			{"2020-09-26", true, false},
+			{"2020-09-27", false, false},
+			{"2021-03-27", false, true},
+			{"2021-03-28T00:00:00", false, true},
+		}
+
+		for _, tc := range tests {
+			instant := Period{Instant: stringPtr(tc.instant)}
+
+			isStart, err := instant.IsStartOf(duration)
+			require.NoError(t, err)
+			assert.Equal(t, tc.start, isStart, tc.instant)
+
+			isEnd, err := instant.IsEndOf(duration)
+			require.NoError(t, err)
+			assert.Equal(t, tc.end, isEnd, tc.instant)
+		}
+
+		_, err := duration.IsStartOf(duration)
+		assert.ErrorIs(t, err, ErrMissingPeriodDate)
+	})
+}
+
+func TestPeriodValidate(t *testing.T) {
+	testCases := []struct {
+		name   string
+		period Period
+		err    string
+	}{
+		{"instant", Period{Instant: stringPtr("2021-03-27")}, ""},
+		{"duration", Period{StartDate: stringPtr("2020-12-27"), EndDate: stringPtr("2021-03-27")}, ""},
+		{"one day duration", Period{StartDate: stringPtr("2021-03-27"), EndDate: stringPtr("2021-03-27")}, ""},
+		{"forever", Period{Forever: &struct{}{}}, ""},
+		{"empty", Period{}, "invalid period: no instant, duration or forever"},
+		{"instant and forever", Period{Instant: stringPtr("2021-03-27"), Forever: &struct{}{}}, "invalid period: more than one of an instant, a duration or forever"},
+		{"instant and duration", Period{Instant: stringPtr("2021-03-27"), EndDate: stringPtr("2021-03-27")}, "invalid period: more than one of an instant, a duration or forever"},
+		{"missing startDate", Period{EndDate: stringPtr("2021-03-27")}, "invalid period: duration without a startDate"},
+		{"missing endDate", Period{StartDate: stringPtr("2021-03-27")}, "invalid period: duration without an endDate"},
+		{"malformed instant", Period{Instant: stringPtr("03/27/2021")}, `invalid period: instant: invalid period date: "03/27/2021"`},
+		{"malformed startDate", Period{StartDate: stringPtr("2020-13-27"), EndDate: stringPtr("2021-03-27")}, `invalid period: startDate: invalid period date: "2020-13-27"`},
+		{"malformed endDate", Period{StartDate: stringPtr("2020-12-27"), EndDate: stringPtr("")}, `invalid period: endDate: invalid period date: ""`},
+		{"startDate after endDate", Period{StartDate: stringPtr("2021-03-27"), EndDate: stringPtr("2020-12-27")}, "invalid period: endDate 2020-12-27 isn't after startDate 2021-03-27"},
+		{"empty duration", Period{StartDate: stringPtr("2021-03-27T12:00:00"), EndDate: stringPtr("2021-03-27T12:00:00")}, "invalid period: endDate 2021-03-27T12:00:00 isn't after startDate 2021-03-27T12:00:00"},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			err := testCase.period.Validate()
+			if testCase.err == "" {
+				assert.NoError(t, err)
+				return
+			}
+
+			assert.ErrorIs(t, err, ErrInvalidPeriod)
+			assert.EqualError(t, err, testCase.err)
+		})
+	}
+}
@@ -0,0 +1,51 @@
+package xbrl
+
+import (
+	"encoding/xml"
+	"fmt"
+)
+
+// Position is the location of an element in the document it was decoded from, so problems can be traced back to the source.
+// It's only set for elements that are decoded as part of an XBRL, RawXBRL or InlineXBRL document, or by a Decoder.
+// The line and column are reported by xml.Decoder.InputPos(), which was added in Go 1.19. When this package is built with
+// an older version of Go, only the Offset is known, and IsValid() returns false.
+type Position struct {
+	// Line is the line of the element's start tag, starting at 1.
+	Line int
+
+	// Column is the column of the '<' of the element's start tag in bytes (not characters), starting at 1.
+	Column int
+
+	// Offset is the byte offset of the '<' of the element's start tag from the start of the document, as reported by xml.Decoder.InputOffset().
+	Offset int64
+}
+
+// IsValid returns true if the position was set, which isn't the case for elements that were decoded on their own.
+func (p Position) IsValid() bool {
+	return p.Line > 0
+}
+
+// String returns the position as "line 12, column 5 (offset 345)".
+func (p Position) String() string {
+	if !p.IsValid() {
+		return "unknown position"
+	}
+
+	return fmt.Sprintf("line %d, column %d (offset %d)", p.Line, p.Column, p.Offset)
+}
+
+// describePosition returns " at <position>" if the position is known, for error messages.
+func describePosition(pos Position) string {
+	if !pos.IsValid() {
+		return ""
+	}
+
+	return " at " + pos.String()
+}
+
+// decoderPosition returns the position of the token d will return next, so it has to be called before d.Token().
+// This works because the position of a decoder is right after the last token it returned.
+func decoderPosition(d *xml.Decoder) Position {
+	line, column := inputPos(d)
+	return Position{Line: line, Column: column, Offset: d.InputOffset()}
+}
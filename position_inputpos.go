@@ -0,0 +1,11 @@
+//go:build go1.19
+// +build go1.19
+
+package xbrl
+
+import "encoding/xml"
+
+// inputPos returns the line and column of the position of d.
+func inputPos(d *xml.Decoder) (line, column int) {
+	return d.InputPos()
+}
@@ -0,0 +1,11 @@
+//go:build !go1.19
+// +build !go1.19
+
+package xbrl
+
+import "encoding/xml"
+
+// inputPos returns zeros, since xml.Decoder.InputPos() isn't available before Go 1.19.
+func inputPos(d *xml.Decoder) (line, column int) {
+	return 0, 0
+}
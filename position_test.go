@@ -0,0 +1,151 @@
+package xbrl
+
+import (
+	"encoding/xml"
+	"io"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPosition(t *testing.T) {
+	// language=xml
+	const doc = `<xbrl xmlns:ci="http://www.xbrl.org/us/gaap/ci/2003/usfr-ci-2003">
+    <context id="c1"><entity><identifier scheme="http://www.sec.gov/CIK">0000320193</identifier></entity><period><instant>2021-04-16</instant></period></context>
+    <unit id="u1"><measure>shares</measure></unit>
+    <ci:assets id="f1" precision="3" unitRef="u1" contextRef="c1">727</ci:assets>
+    <ci:tuple>
+        <ci:item contextRef="c1">a</ci:item><ci:item contextRef="c1">é</ci:item><ci:item contextRef="c1">b</ci:item>
+    </ci:tuple>
+</xbrl>`
+
+	t.Run("xbrl", func(t *testing.T) {
+		var processed XBRL
+		require.NoError(t, xml.Unmarshal([]byte(doc), &processed))
+
+		assert.Equal(t, Position{Line: 2, Column: 5, Offset: 71}, processed.ContextsByID["c1"].Position)
+		assert.Equal(t, Position{Line: 3, Column: 5, Offset: 233}, processed.UnitsByID["u1"].Position)
+
+		require.Len(t, processed.Facts, 4)
+		assert.Equal(t, Position{Line: 4, Column: 5, Offset: 284}, processed.Facts[0].Position)
+		assert.Equal(t, Position{Line: 6, Column: 9, Offset: 385}, processed.Facts[1].Position)
+		assert.Equal(t, Position{Line: 6, Column: 45, Offset: 421}, processed.Facts[2].Position)
+		// Columns are counted in bytes, and é is 2 bytes in UTF-8.
+		assert.Equal(t, Position{Line: 6, Column: 82, Offset: 458}, processed.Facts[3].Position)
+
+		require.Len(t, processed.Tuples, 1)
+		assert.Equal(t, Position{Line: 5, Column: 5, Offset: 366}, processed.Tuples[0].Position)
+
+		for _, fact := range processed.Facts {
+			assert.True(t, strings.HasPrefix(doc[fact.Position.Offset:], "<ci:"))
+		}
+	})
+
+	t.Run("decoder", func(t *testing.T) {
+		decoder := NewDecoder(strings.NewReader(doc))
+		var positions []Position
+		for {
+			element, err := decoder.Next()
+			if err == io.EOF {
+				break
+			}
+			require.NoError(t, err)
+
+			switch e := element.(type) {
+			case *Context:
+				positions = append(positions, e.Position)
+			case *Unit:
+				positions = append(positions, e.Position)
+			case *Fact:
+				positions = append(positions, e.Position)
+			}
+		}
+
+		assert.Equal(t, []Position{
+			{Line: 2, Column: 5, Offset: 71},
+			{Line: 3, Column: 5, Offset: 233},
+			{Line: 4, Column: 5, Offset: 284},
+			{Line: 6, Column: 9, Offset: 385},
+			{Line: 6, Column: 45, Offset: 421},
+			{Line: 6, Column: 82, Offset: 458},
+		}, positions)
+	})
+
+	t.Run("inline xbrl", func(t *testing.T) {
+		docBytes, err := os.ReadFile("test_data/simple_inline_xbrl.htm")
+		require.NoError(t, err)
+
+		var inline InlineXBRL
+		require.NoError(t, xml.Unmarshal(docBytes, &inline))
+
+		for _, fact := range inline.Facts {
+			require.True(t, fact.Position.IsValid())
+			assert.True(t, strings.HasPrefix(string(docBytes[fact.Position.Offset:]), "<ix:"), fact.Name.String())
+		}
+
+		assert.True(t, strings.HasPrefix(string(docBytes[inline.ContextsByID["c1"].Position.Offset:]), `<xbrli:context id="c1">`))
+		assert.True(t, strings.HasPrefix(string(docBytes[inline.UnitsByID["usd"].Position.Offset:]), `<xbrli:unit id="usd">`))
+	})
+
+	t.Run("not set when decoded on its own", func(t *testing.T) {
+		var fact Fact
+		require.NoError(t, xml.Unmarshal([]byte(`<ci:assets contextRef="c1">727</ci:assets>`), &fact))
+		assert.False(t, fact.Position.IsValid())
+		assert.Equal(t, "unknown position", fact.Position.String())
+	})
+}
+
+func TestPositionInErrors(t *testing.T) {
+	t.Run("validate", func(t *testing.T) {
+		xbrlBytes, err := os.ReadFile("test_data/invalid_xbrl.xml")
+		require.NoError(t, err)
+
+		var content XBRL
+		require.NoError(t, xml.Unmarshal(xbrlBytes, &content))
+		assert.EqualError(t, content.Validate(), "fact ci:assets at line 3, column 5 (offset 117) references non-existent context: c1 (and 1 more issue)")
+	})
+
+	t.Run("numeric value", func(t *testing.T) {
+		// language=xml
+		const doc = `<xbrl>
+    <ci:assets id="f1" contextRef="c1" unitRef="u1" decimals="0">seven</ci:assets>
+</xbrl>`
+
+		var content XBRL
+		require.NoError(t, xml.Unmarshal([]byte(doc), &content))
+		require.Len(t, content.Facts, 1)
+
+		_, err := content.Facts[0].NumericValue()
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "fact ci:assets (id f1) at line 2, column 5 (offset 11): ")
+	})
+
+	t.Run("decoding", func(t *testing.T) {
+		// language=xml
+		const doc = `<xbrl>
+    <ci:fraction contextRef="c1" unitRef="u1"><numerator>one</numerator><denominator>3</denominator></ci:fraction>
+</xbrl>`
+
+		var content XBRL
+		err := xml.Unmarshal([]byte(doc), &content)
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "fact at line 2, column 5 (offset 11): ")
+	})
+
+	t.Run("decoding inline xbrl", func(t *testing.T) {
+		// language=xml
+		const doc = `<html xmlns:ix="http://www.xbrl.org/2013/inlineXBRL" xmlns:ci="http://www.xbrl.org/us/gaap/ci/2003/usfr-ci-2003">
+<body>
+    <ix:nonFraction name="ci:assets" contextRef="c1" unitRef="u1" decimals="0" sign="+">727</ix:nonFraction>
+</body>
+</html>`
+
+		var inline InlineXBRL
+		err := xml.Unmarshal([]byte(doc), &inline)
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "fact at line 3, column 5 (offset 125): ")
+	})
+}
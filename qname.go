@@ -0,0 +1,159 @@
+package xbrl
+
+import (
+	"encoding/xml"
+	"strings"
+)
+
+// QName is a qualified name of a concept, measure or dimension, ie "us-gaap:Revenues".
+// https://www.w3.org/TR/xmlschema-2/#QName
+//
+// The prefix that a filer picks for a namespace is arbitrary, so use Equal() or ExpandedName() to compare QNames
+// rather than comparing them with ==.
+type QName struct {
+	// Space is the namespace URI that the prefix is bound to, ie "http://fasb.org/us-gaap/2020-01-31".
+	// It's empty if the prefix isn't declared in the document, or the QName was parsed without the document's namespace declarations.
+	Space string
+
+	// Local is the local part of the name.
+	Local string
+
+	// Prefix is the namespace prefix as written in the document. It's empty if the name is unprefixed.
+	Prefix string
+}
+
+// ParseQName splits a "prefix:local" string into a QName without resolving its namespace. Surrounding whitespace is ignored.
+// If there's no colon, the whole string is the local part.
+func ParseQName(value string) QName {
+	value = strings.TrimSpace(value)
+	if index := strings.IndexRune(value, ':'); index != -1 {
+		return QName{Prefix: value[:index], Local: value[index+1:]}
+	}
+
+	return QName{Local: value}
+}
+
+// String returns the QName in its "prefix:local" form, or just the local part if there is no prefix.
+func (q QName) String() string {
+	if q.Prefix == "" {
+		return q.Local
+	}
+
+	return q.Prefix + ":" + q.Local
+}
+
+// ExpandedName returns the namespace URI and local part of the QName, which identify it regardless of its prefix.
+// It's useful as a map key.
+func (q QName) ExpandedName() xml.Name {
+	return xml.Name{Space: q.Space, Local: q.Local}
+}
+
+// Equal returns true if q and other have the same namespace and local part, regardless of their prefixes.
+// If neither namespace is known, the prefixes are compared instead.
+func (q QName) Equal(other QName) bool {
+	if q.Local != other.Local || q.Space != other.Space {
+		return false
+	}
+
+	return q.Space != "" || q.Prefix == other.Prefix
+}
+
+// namespaceScope holds the namespace declarations of the open elements of a document, outermost first.
+// The xml:lang attributes of the elements are kept in it too (see langKey), since they're inherited the same way.
+type namespaceScope []map[string]string
+
+// langKey is the key of xml:lang attributes in a namespaceScope. It can't clash with a prefix, since prefixes can't contain a colon.
+const langKey = "xml:lang"
+
+// with returns the scope inside of the element start, which includes the namespaces it declares.
+// The receiver is left untouched.
+func (s namespaceScope) with(start xml.StartElement) namespaceScope {
+	namespaces := declaredNamespaces(start)
+	if namespaces == nil {
+		return s
+	}
+
+	return append(s[:len(s):len(s)], namespaces)
+}
+
+// lookup returns the namespace URI bound to prefix. The empty prefix is the default namespace.
+func (s namespaceScope) lookup(prefix string) (string, bool) {
+	for i := len(s) - 1; i >= 0; i-- {
+		if space, exists := s[i][prefix]; exists {
+			return space, true
+		}
+	}
+
+	return "", false
+}
+
+// lang returns the value of the innermost xml:lang attribute, or an empty string if there is none.
+func (s namespaceScope) lang() string {
+	lang, _ := s.lookup(langKey)
+	return lang
+}
+
+// resolve sets the Space of q to the namespace URI bound to its prefix.
+// Unprefixed QNames are in the default namespace, as in XML Schema. If the prefix isn't declared, Space is left empty.
+func (s namespaceScope) resolve(q QName) QName {
+	q.Space, _ = s.lookup(q.Prefix)
+	return q
+}
+
+// qname returns the QName of an element name that was already resolved by xml.Decoder.
+// The decoder doesn't keep the prefix, so it's found by looking for the innermost declaration of the namespace.
+// If the decoder couldn't resolve the prefix, it leaves the prefix in name.Space, which is kept as the QName's Prefix instead.
+func (s namespaceScope) qname(name xml.Name) QName {
+	for i := len(s) - 1; i >= 0; i-- {
+		found := false
+		var best string
+		for prefix, space := range s[i] {
+			if prefix == langKey {
+				continue
+			}
+
+			// A prefix could be redeclared by an inner element, so make sure this one is still in scope.
+			if inScope, _ := s.lookup(prefix); space != name.Space || inScope != space {
+				continue
+			}
+
+			// Pick the same prefix every time if one element binds several prefixes to the namespace.
+			if !found || prefix < best {
+				found, best = true, prefix
+			}
+		}
+
+		if found {
+			return QName{Space: name.Space, Local: name.Local, Prefix: best}
+		}
+	}
+
+	return QName{Local: name.Local, Prefix: name.Space}
+}
+
+// declaredNamespaces returns the namespace prefixes declared by the xmlns attributes of an element.
+// The default namespace is stored under the empty prefix, and the element's xml:lang attribute under langKey.
+func declaredNamespaces(start xml.StartElement) map[string]string {
+	var namespaces map[string]string
+	for _, attr := range start.Attr {
+		var prefix string
+		switch {
+		case attr.Name.Space == "xmlns":
+			prefix = attr.Name.Local
+		case attr.Name.Space == "" && attr.Name.Local == "xmlns":
+			prefix = ""
+		case (attr.Name.Space == namespaceXML || attr.Name.Space == "xml") && attr.Name.Local == "lang":
+			prefix = langKey
+		default:
+			continue
+		}
+
+		if namespaces == nil {
+			namespaces = make(map[string]string)
+		}
+
+		namespaces[prefix] = attr.Value
+	}
+
+	return namespaces
+}
@@ -0,0 +1,102 @@
+package xbrl
+
+import (
+	"encoding/xml"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestQNameEqual(t *testing.T) {
+	gaap := QName{Space: "http://fasb.org/us-gaap/2020-01-31", Local: "Revenues", Prefix: "us-gaap"}
+
+	assert.True(t, gaap.Equal(QName{Space: "http://fasb.org/us-gaap/2020-01-31", Local: "Revenues", Prefix: "gaap"}))
+	assert.False(t, gaap.Equal(QName{Space: "http://fasb.org/us-gaap/2021-01-31", Local: "Revenues", Prefix: "us-gaap"}))
+	assert.False(t, gaap.Equal(QName{Space: "http://fasb.org/us-gaap/2020-01-31", Local: "Assets", Prefix: "us-gaap"}))
+
+	// Without namespaces, only the prefixes can tell two names apart.
+	assert.True(t, ParseQName("my:Thing").Equal(ParseQName("my:Thing")))
+	assert.False(t, ParseQName("my:Thing").Equal(ParseQName("your:Thing")))
+
+	assert.Equal(t, xml.Name{Space: "http://fasb.org/us-gaap/2020-01-31", Local: "Revenues"}, gaap.ExpandedName())
+	assert.Equal(t, "us-gaap:Revenues", gaap.String())
+}
+
+func TestResolveQNames(t *testing.T) {
+	t.Run("different prefixes for the same namespace", func(t *testing.T) {
+		// language=xml
+		xbrlXML := `<xbrl xmlns="http://www.xbrl.org/2003/instance" xmlns:us-gaap="http://fasb.org/us-gaap/2020-01-31" xmlns:money="http://www.xbrl.org/2003/iso4217" xmlns:xbrldi="http://xbrl.org/2006/xbrldi">
+    <context id="c1">
+        <entity>
+            <identifier scheme="http://www.sec.gov/CIK">0000320193</identifier>
+            <segment>
+                <xbrldi:explicitMember dimension="us-gaap:StatementClassOfStockAxis">us-gaap:CommonStockMember</xbrldi:explicitMember>
+                <xbrldi:typedMember dimension="us-gaap:ContractAxis"><us-gaap:ContractId>1</us-gaap:ContractId></xbrldi:typedMember>
+            </segment>
+        </entity>
+        <period>
+            <instant>2021-03-27</instant>
+        </period>
+    </context>
+    <unit id="usdPerShare">
+        <divide>
+            <unitNumerator>
+                <measure>money:USD</measure>
+            </unitNumerator>
+            <unitDenominator>
+                <measure>shares</measure>
+            </unitDenominator>
+        </divide>
+    </unit>
+
+    <us-gaap:EarningsPerShareBasic contextRef="c1" unitRef="usdPerShare" decimals="2">1.41</us-gaap:EarningsPerShareBasic>
+    <gaap:EarningsPerShareBasic xmlns:gaap="http://fasb.org/us-gaap/2020-01-31" contextRef="c1" unitRef="usdPerShare" decimals="2">1.41</gaap:EarningsPerShareBasic>
+    <undeclared:Thing contextRef="c1">text</undeclared:Thing>
+</xbrl>`
+
+		var content XBRL
+		require.NoError(t, xml.Unmarshal([]byte(xbrlXML), &content))
+		require.Len(t, content.Facts, 3)
+
+		first, second := content.Facts[0].Name, content.Facts[1].Name
+		assert.Equal(t, QName{Space: "http://fasb.org/us-gaap/2020-01-31", Local: "EarningsPerShareBasic", Prefix: "us-gaap"}, first)
+		assert.Equal(t, "gaap", second.Prefix)
+		assert.True(t, first.Equal(second))
+
+		assert.Equal(t, QName{Local: "Thing", Prefix: "undeclared"}, content.Facts[2].Name)
+
+		unit := content.UnitsByID["usdPerShare"]
+		assert.Equal(t, QName{Space: "http://www.xbrl.org/2003/iso4217", Local: "USD", Prefix: "money"}, unit.Divide.Numerator[0].Name)
+		assert.Equal(t, QName{Space: NamespaceXBRLI, Local: "shares"}, unit.Divide.Denominator[0].Name)
+		assert.Equal(t, "USD / shares", unit.String())
+
+		dimensions := content.ContextsByID["c1"].Dimensions()
+		require.Len(t, dimensions, 2)
+		assert.Equal(t, QName{Space: "http://fasb.org/us-gaap/2020-01-31", Local: "StatementClassOfStockAxis", Prefix: "us-gaap"}, dimensions[0].Dimension)
+		assert.Equal(t, QName{Space: "http://fasb.org/us-gaap/2020-01-31", Local: "CommonStockMember", Prefix: "us-gaap"}, dimensions[0].Member)
+		assert.Equal(t, "http://fasb.org/us-gaap/2020-01-31", dimensions[1].Dimension.Space)
+	})
+
+	t.Run("inline facts", func(t *testing.T) {
+		// language=xml
+		inlineXML := `<html xmlns:ix="http://www.xbrl.org/2013/inlineXBRL" xmlns:xbrli="http://www.xbrl.org/2003/instance" xmlns:iso4217="http://www.xbrl.org/2003/iso4217">
+	<ix:header>
+		<ix:resources>
+			<xbrli:unit id="usd">
+				<xbrli:measure>iso4217:USD</xbrli:measure>
+			</xbrli:unit>
+		</ix:resources>
+	</ix:header>
+	<div xmlns:gaap="http://fasb.org/us-gaap/2020-01-31">
+		<ix:nonFraction name="gaap:Revenues" contextRef="c1" unitRef="usd" decimals="0">100</ix:nonFraction>
+	</div>
+</html>`
+
+		var content InlineXBRL
+		require.NoError(t, xml.Unmarshal([]byte(inlineXML), &content))
+		require.Len(t, content.Facts, 1)
+		assert.Equal(t, QName{Space: "http://fasb.org/us-gaap/2020-01-31", Local: "Revenues", Prefix: "gaap"}, content.Facts[0].Name)
+		assert.Equal(t, QName{Space: "http://www.xbrl.org/2003/iso4217", Local: "USD", Prefix: "iso4217"}, content.UnitsByID["usd"].Measures[0].Name)
+	})
+}
@@ -0,0 +1,39 @@
+package xbrl
+
+// SchemaRef points to a taxonomy schema that the XBRL document is built against, usually the filer's extension taxonomy entry point.
+// The schemas that it imports make up the rest of the document's taxonomy (its DTS).
+//
+// For example:
+// <link:schemaRef xlink:type="simple" xlink:href="aapl-20210327.xsd"/>
+//
+// https://www.xbrl.org/Specification/XBRL-2.1/REC-2003-12-31/XBRL-2.1-REC-2003-12-31+corrected-errata-2013-02-20.html#_4.2
+type SchemaRef struct {
+	// Href is the xlink:href of the schema, which is relative to the document's location unless it's an absolute URL.
+	Href string `xml:"href,attr"`
+}
+
+// LinkbaseRef points to a linkbase (labels, calculations, presentation, etc.) that's part of the document's taxonomy.
+// https://www.xbrl.org/Specification/XBRL-2.1/REC-2003-12-31/XBRL-2.1-REC-2003-12-31+corrected-errata-2013-02-20.html#_4.3
+type LinkbaseRef struct {
+	Href string `xml:"href,attr"`
+
+	// Role is the optional xlink:role that tells what kind of linkbase is referenced, ie "http://www.xbrl.org/2003/role/calculationLinkbaseRef".
+	Role string `xml:"role,attr"`
+
+	// ArcRole is the xlink:arcrole, which is always "http://www.w3.org/1999/xlink/properties/linkbase" in valid documents.
+	ArcRole string `xml:"arcrole,attr"`
+}
+
+// RoleRef points to the definition of a custom role that's used in the document's footnote links.
+// https://www.xbrl.org/Specification/XBRL-2.1/REC-2003-12-31/XBRL-2.1-REC-2003-12-31+corrected-errata-2013-02-20.html#_4.4
+type RoleRef struct {
+	Href    string `xml:"href,attr"`
+	RoleURI string `xml:"roleURI,attr"`
+}
+
+// ArcRoleRef points to the definition of a custom arcrole that's used in the document's footnote links.
+// https://www.xbrl.org/Specification/XBRL-2.1/REC-2003-12-31/XBRL-2.1-REC-2003-12-31+corrected-errata-2013-02-20.html#_4.5
+type ArcRoleRef struct {
+	Href       string `xml:"href,attr"`
+	ArcRoleURI string `xml:"arcroleURI,attr"`
+}
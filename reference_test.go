@@ -0,0 +1,54 @@
+package xbrl
+
+import (
+	"encoding/xml"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestUnmarshalReferences(t *testing.T) {
+	t.Run("all reference types", func(t *testing.T) {
+		// language=xml
+		xbrlXML := `<xbrl xmlns="http://www.xbrl.org/2003/instance" xmlns:link="http://www.xbrl.org/2003/linkbase" xmlns:xlink="http://www.w3.org/1999/xlink">
+    <link:schemaRef xlink:type="simple" xlink:href="aapl-20210327.xsd"/>
+    <link:linkbaseRef xlink:type="simple" xlink:href="aapl-20210327_cal.xml" xlink:role="http://www.xbrl.org/2003/role/calculationLinkbaseRef" xlink:arcrole="http://www.w3.org/1999/xlink/properties/linkbase"/>
+    <link:roleRef xlink:type="simple" xlink:href="aapl-20210327.xsd#Footnote" roleURI="http://www.apple.com/role/Footnote"/>
+    <link:arcroleRef xlink:type="simple" xlink:href="aapl-20210327.xsd#explains" arcroleURI="http://www.apple.com/arcrole/explains"/>
+</xbrl>`
+
+		var content XBRL
+		require.NoError(t, xml.Unmarshal([]byte(xbrlXML), &content))
+		assert.Empty(t, content.Facts)
+
+		assert.Equal(t, []SchemaRef{{Href: "aapl-20210327.xsd"}}, content.SchemaRefs)
+		assert.Equal(t, []LinkbaseRef{{
+			Href:    "aapl-20210327_cal.xml",
+			Role:    "http://www.xbrl.org/2003/role/calculationLinkbaseRef",
+			ArcRole: "http://www.w3.org/1999/xlink/properties/linkbase",
+		}}, content.LinkbaseRefs)
+		assert.Equal(t, []RoleRef{{Href: "aapl-20210327.xsd#Footnote", RoleURI: "http://www.apple.com/role/Footnote"}}, content.RoleRefs)
+		assert.Equal(t, []ArcRoleRef{{Href: "aapl-20210327.xsd#explains", ArcRoleURI: "http://www.apple.com/arcrole/explains"}}, content.ArcRoleRefs)
+	})
+
+	t.Run("apple 10-q", func(t *testing.T) {
+		xbrlBytes, err := os.ReadFile("test_data/aapl-20210327_htm.xml")
+		require.NoError(t, err)
+
+		var content XBRL
+		require.NoError(t, xml.Unmarshal(xbrlBytes, &content))
+		assert.Equal(t, []SchemaRef{{Href: "aapl-20210327.xsd"}}, content.SchemaRefs)
+	})
+
+	t.Run("inline references", func(t *testing.T) {
+		f, err := os.Open("test_data/simple_inline_xbrl.htm")
+		require.NoError(t, err)
+		defer f.Close()
+
+		var content InlineXBRL
+		require.NoError(t, xml.NewDecoder(f).Decode(&content))
+		assert.Equal(t, []SchemaRef{{Href: "aapl-20210327.xsd"}}, content.SchemaRefs)
+	})
+}
@@ -0,0 +1,671 @@
+package xbrl
+
+import (
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+)
+
+// Namespaces of the transformation registries whose formats are included in DefaultTransforms.
+const (
+	// NamespaceTransformationRegistry1 is the namespace of the XBRL Transformation Registry 1.
+	// https://www.xbrl.org/Specification/inlineXBRL-transformationRegistry/REC-2010-04-20/inlineXBRL-transformationRegistry-REC-2010-04-20.html
+	NamespaceTransformationRegistry1 = "http://www.xbrl.org/inlineXBRL/transformation/2010-04-20"
+	// NamespaceTransformationRegistry2 is the namespace of the XBRL Transformation Registry 2.
+	NamespaceTransformationRegistry2 = "http://www.xbrl.org/inlineXBRL/transformation/2011-07-31"
+	// NamespaceTransformationRegistry3 is the namespace of the XBRL Transformation Registry 3.
+	NamespaceTransformationRegistry3 = "http://www.xbrl.org/inlineXBRL/transformation/2015-02-26"
+	// NamespaceTransformationRegistry4 is the namespace of the XBRL Transformation Registry 4.
+	NamespaceTransformationRegistry4 = "http://www.xbrl.org/inlineXBRL/transformation/2020-02-12"
+	// NamespaceTransformationRegistry5 is the namespace of the XBRL Transformation Registry 5.
+	NamespaceTransformationRegistry5 = "http://www.xbrl.org/inlineXBRL/transformation/2022-02-16"
+
+	// NamespaceTransformationSEC is the namespace of the SEC's own transformations (ixt-sec).
+	// https://www.sec.gov/info/edgar/edgarfm-vol2-v60.pdf (section 5.2.5.12)
+	NamespaceTransformationSEC = "http://www.sec.gov/inlineXBRL/transformation/2015-08-31"
+)
+
+// ErrUnknownTransform is returned when an inline fact uses a format that isn't in the TransformRegistry.
+var ErrUnknownTransform = errors.New("unknown transformation format")
+
+// TransformFunc converts the displayed value of an inline fact into its XBRL lexical value.
+// It returns an error when the displayed value does not match the format that the TransformFunc implements.
+type TransformFunc func(displayed string) (string, error)
+
+// TransformRegistry holds the TransformFuncs available to an inline document, keyed by the expanded name of their format.
+// The format attribute of an inline fact (ie `format="ixt:num-dot-decimal"`) is resolved to a namespace URI before it is looked up.
+//
+// Use DefaultTransforms() to get a registry containing the XBRL Transformation Registries 1-5 and the SEC transformations,
+// then Register any custom formats on top of it.
+type TransformRegistry map[xml.Name]TransformFunc
+
+// TransformError is returned when a displayed value cannot be transformed into an XBRL lexical value.
+type TransformError struct {
+	// Format is the expanded name of the format that was applied.
+	Format xml.Name
+	// Value is the displayed value that could not be transformed.
+	Value string
+	// Err describes why the transformation failed.
+	Err error
+}
+
+// Error implements the error interface.
+func (e *TransformError) Error() string {
+	return fmt.Sprintf("cannot transform %q with format {%s}%s: %v", e.Value, e.Format.Space, e.Format.Local, e.Err)
+}
+
+// Unwrap returns the underlying error so TransformErrors work with errors.Is and errors.As.
+func (e *TransformError) Unwrap() error {
+	return e.Err
+}
+
+// Register adds a TransformFunc for the format with the given namespace and local name, replacing any existing one.
+func (r TransformRegistry) Register(space, local string, transform TransformFunc) {
+	r[xml.Name{Space: space, Local: local}] = transform
+}
+
+// Transform applies the TransformFunc registered for format to a displayed value.
+// The error is always a *TransformError, which wraps ErrUnknownTransform if the format isn't registered.
+func (r TransformRegistry) Transform(format xml.Name, displayed string) (string, error) {
+	transform, exists := r[format]
+	if !exists {
+		return "", &TransformError{Format: format, Value: displayed, Err: ErrUnknownTransform}
+	}
+
+	value, err := transform(displayed)
+	if err != nil {
+		return "", &TransformError{Format: format, Value: displayed, Err: err}
+	}
+
+	return value, nil
+}
+
+// defaultTransforms is used by InlineXBRL when no TransformRegistry is provided.
+var defaultTransforms = DefaultTransforms()
+
+// DefaultTransforms returns a new TransformRegistry with the formats of the XBRL Transformation Registries 1 through 5
+// and the SEC transformation registry.
+//
+// Month names are recognized in English, Danish, Dutch, Finnish, French, German, Italian, Norwegian, Polish, Portuguese, Spanish and Swedish.
+// Formats that depend on other languages or calendars (ie Hindi month names or the Indian national calendar) are not included,
+// but can be added to the returned registry with Register.
+func DefaultTransforms() TransformRegistry {
+	registry := make(TransformRegistry)
+
+	registerTransformationRegistry1(registry)
+	registerTransformationRegistry2(registry, NamespaceTransformationRegistry2)
+	registerTransformationRegistry2(registry, NamespaceTransformationRegistry3)
+	registerTransformationRegistry3(registry)
+	registerTransformationRegistry4(registry, NamespaceTransformationRegistry4)
+	registerTransformationRegistry4(registry, NamespaceTransformationRegistry5)
+	registerTransformationRegistry5(registry)
+	registerSECTransforms(registry)
+
+	return registry
+}
+
+func registerTransformationRegistry1(r TransformRegistry) {
+	ns := NamespaceTransformationRegistry1
+
+	r.Register(ns, "dateslashus", dateTransform("MDY", nil))
+	r.Register(ns, "dateslasheu", dateTransform("DMY", nil))
+	r.Register(ns, "datedotus", dateTransform("MDY", nil))
+	r.Register(ns, "datedoteu", dateTransform("DMY", nil))
+	r.Register(ns, "datelongus", dateTransform("MDY", monthNamesEN))
+	r.Register(ns, "dateshortus", dateTransform("MDY", monthNamesEN))
+	r.Register(ns, "datelonguk", dateTransform("DMY", monthNamesEN))
+	r.Register(ns, "dateshortuk", dateTransform("DMY", monthNamesEN))
+	r.Register(ns, "datelongmonthdayus", dateTransform("MD", monthNamesEN))
+	r.Register(ns, "dateshortmonthdayus", dateTransform("MD", monthNamesEN))
+	r.Register(ns, "datelongdaymonthuk", dateTransform("DM", monthNamesEN))
+	r.Register(ns, "dateshortdaymonthuk", dateTransform("DM", monthNamesEN))
+	r.Register(ns, "dateslashmonthdayus", dateTransform("MD", nil))
+	r.Register(ns, "dateslashdaymontheu", dateTransform("DM", nil))
+	r.Register(ns, "datelongyearmonth", dateTransform("YM", monthNamesEN))
+	r.Register(ns, "dateshortyearmonth", dateTransform("YM", monthNamesEN))
+	r.Register(ns, "datelongmonthyear", dateTransform("MY", monthNamesEN))
+	r.Register(ns, "dateshortmonthyear", dateTransform("MY", monthNamesEN))
+
+	r.Register(ns, "numcommadot", numberTransform(`^[0-9]{1,3}(?:,[0-9]{3})*(?:\.[0-9]+)?$`, '.'))
+	r.Register(ns, "numdotcomma", numberTransform(`^[0-9]{1,3}(?:\.[0-9]{3})*(?:,[0-9]+)?$`, ','))
+	r.Register(ns, "numspacedot", numberTransform(`^[0-9]{1,3}(?:[ \x{00A0}][0-9]{3})*(?:\.[0-9]+)?$`, '.'))
+	r.Register(ns, "numspacecomma", numberTransform(`^[0-9]{1,3}(?:[ \x{00A0}][0-9]{3})*(?:,[0-9]+)?$`, ','))
+	r.Register(ns, "numcomma", numberTransform(`^[0-9]+(?:,[0-9]+)?$`, ','))
+	r.Register(ns, "numdash", zeroDash)
+}
+
+// registerTransformationRegistry2 registers the formats introduced in Transformation Registry 2,
+// which Transformation Registry 3 repeats under its own namespace.
+func registerTransformationRegistry2(r TransformRegistry, ns string) {
+	r.Register(ns, "booleanfalse", fixedValue("false"))
+	r.Register(ns, "booleantrue", fixedValue("true"))
+	r.Register(ns, "nocontent", fixedValue(""))
+	r.Register(ns, "zerodash", zeroDash)
+
+	r.Register(ns, "datedaymonth", dateTransform("DM", nil))
+	r.Register(ns, "datedaymonthen", dateTransform("DM", monthNamesEN))
+	r.Register(ns, "datedaymonthyear", dateTransform("DMY", nil))
+	r.Register(ns, "datedaymonthyearen", dateTransform("DMY", monthNamesEN))
+	r.Register(ns, "datemonthday", dateTransform("MD", nil))
+	r.Register(ns, "datemonthdayen", dateTransform("MD", monthNamesEN))
+	r.Register(ns, "datemonthdayyear", dateTransform("MDY", nil))
+	r.Register(ns, "datemonthdayyearen", dateTransform("MDY", monthNamesEN))
+	r.Register(ns, "datemonthyearen", dateTransform("MY", monthNamesEN))
+	r.Register(ns, "dateyearmonthen", dateTransform("YM", monthNamesEN))
+	r.Register(ns, "dateyearmonthdaycjk", dateTransform("YMD", nil))
+	r.Register(ns, "dateyearmonthcjk", dateTransform("YM", nil))
+	r.Register(ns, "dateerayearmonthdayjp", japaneseEraDate(true))
+	r.Register(ns, "dateerayearmonthjp", japaneseEraDate(false))
+
+	r.Register(ns, "numdotdecimal", numberTransform(patternDotDecimal, '.'))
+	r.Register(ns, "numcommadecimal", numberTransform(patternCommaDecimal, ','))
+	r.Register(ns, "numunitdecimal", unitDecimal(patternUnitDecimal))
+}
+
+func registerTransformationRegistry3(r TransformRegistry) {
+	ns := NamespaceTransformationRegistry3
+
+	r.Register(ns, "datedaymonthdk", dateTransform("DM", monthNamesDA))
+	r.Register(ns, "datedaymonthyeardk", dateTransform("DMY", monthNamesDA))
+	r.Register(ns, "datemonthyeardk", dateTransform("MY", monthNamesDA))
+	r.Register(ns, "datemonthyear", dateTransform("MY", nil))
+	r.Register(ns, "dateyearmonthday", dateTransform("YMD", nil))
+
+	r.Register(ns, "numdotdecimalin", numberTransform(patternDotDecimalIN, '.'))
+	r.Register(ns, "numunitdecimalin", unitDecimal(patternUnitDecimalIN))
+}
+
+// registerTransformationRegistry4 registers the formats of Transformation Registry 4,
+// which Transformation Registry 5 repeats under its own namespace.
+func registerTransformationRegistry4(r TransformRegistry, ns string) {
+	r.Register(ns, "fixed-empty", fixedValue(""))
+	r.Register(ns, "fixed-false", fixedValue("false"))
+	r.Register(ns, "fixed-true", fixedValue("true"))
+	r.Register(ns, "fixed-zero", fixedValue("0"))
+
+	r.Register(ns, "date-day-month", dateTransform("DM", nil))
+	r.Register(ns, "date-day-month-year", dateTransform("DMY", nil))
+	r.Register(ns, "date-month-day", dateTransform("MD", nil))
+	r.Register(ns, "date-month-day-year", dateTransform("MDY", nil))
+	r.Register(ns, "date-month-year", dateTransform("MY", nil))
+	r.Register(ns, "date-year-month", dateTransform("YM", nil))
+	r.Register(ns, "date-year-month-day", dateTransform("YMD", nil))
+	r.Register(ns, "date-jpn-era-year-month-day", japaneseEraDate(true))
+	r.Register(ns, "date-jpn-era-year-month", japaneseEraDate(false))
+
+	for lang, months := range monthNamesByLanguage {
+		r.Register(ns, "date-day-monthname-"+lang, dateTransform("DM", months))
+		r.Register(ns, "date-day-monthname-year-"+lang, dateTransform("DMY", months))
+		r.Register(ns, "date-monthname-day-"+lang, dateTransform("MD", months))
+		r.Register(ns, "date-monthname-day-year-"+lang, dateTransform("MDY", months))
+		r.Register(ns, "date-monthname-year-"+lang, dateTransform("MY", months))
+		r.Register(ns, "date-year-monthname-"+lang, dateTransform("YM", months))
+		r.Register(ns, "date-year-monthname-day-"+lang, dateTransform("YMD", months))
+	}
+
+	r.Register(ns, "num-dot-decimal", numberTransform(patternDotDecimal, '.'))
+	r.Register(ns, "num-comma-decimal", numberTransform(patternCommaDecimal, ','))
+	r.Register(ns, "num-unit-decimal", unitDecimal(patternUnitDecimal))
+	r.Register(ns, "num-dot-decimal-in", numberTransform(patternDotDecimalIN, '.'))
+	r.Register(ns, "num-unit-decimal-in", unitDecimal(patternUnitDecimalIN))
+}
+
+func registerTransformationRegistry5(r TransformRegistry) {
+	ns := NamespaceTransformationRegistry5
+
+	r.Register(ns, "date-day-monthroman-year", dateTransform("DMY", monthNamesRoman))
+	r.Register(ns, "date-monthroman-year", dateTransform("MY", monthNamesRoman))
+
+	r.Register(ns, "num-dot-decimal-apos", numberTransform(patternDotDecimalApos, '.'))
+	r.Register(ns, "num-comma-decimal-apos", numberTransform(patternCommaDecimalApos, ','))
+	r.Register(ns, "num-unit-decimal-apos", unitDecimal(patternUnitDecimalApos))
+}
+
+// Patterns for the numeric transformations. Digit groups may be separated by a space, a no-break space or the group separator of the format.
+const (
+	patternDotDecimal       = `^(?:[0-9]{1,3}(?:[ ,\x{00A0}]?[0-9]{3})*(?:\.[0-9]*)?|\.[0-9]+)$`
+	patternCommaDecimal     = `^(?:[0-9]{1,3}(?:[ .\x{00A0}]?[0-9]{3})*(?:,[0-9]*)?|,[0-9]+)$`
+	patternDotDecimalApos   = `^(?:[0-9]{1,3}(?:[ ,'\x{2019}\x{00A0}]?[0-9]{3})*(?:\.[0-9]*)?|\.[0-9]+)$`
+	patternCommaDecimalApos = `^(?:[0-9]{1,3}(?:[ .'\x{2019}\x{00A0}]?[0-9]{3})*(?:,[0-9]*)?|,[0-9]+)$`
+	patternDotDecimalIN     = `^(?:(?:[0-9]{1,2}(?:[ ,\x{00A0}]?[0-9]{2})*[ ,\x{00A0}]?)?[0-9]{1,3}(?:\.[0-9]*)?|\.[0-9]+)$`
+
+	patternUnitDecimal     = `^([0-9]{1,3}(?:[ ,.\x{00A0}]?[0-9]{3})*)(?:[^0-9]+([0-9]{1,2}))?[^0-9]*$`
+	patternUnitDecimalApos = `^([0-9]{1,3}(?:[ ,.'\x{2019}\x{00A0}]?[0-9]{3})*)(?:[^0-9]+([0-9]{1,2}))?[^0-9]*$`
+	patternUnitDecimalIN   = `^((?:[0-9]{1,2}(?:[ ,\x{00A0}]?[0-9]{2})*[ ,\x{00A0}]?)?[0-9]{1,3})(?:[^0-9]+([0-9]{1,2}))?[^0-9]*$`
+)
+
+// dashes are the characters accepted by the zero dash transformations.
+const dashes = "-֊־‐‑‒–—―﹘﹣－"
+
+// fixedValue returns a TransformFunc that ignores the displayed value and always returns value.
+func fixedValue(value string) TransformFunc {
+	return func(string) (string, error) {
+		return value, nil
+	}
+}
+
+// zeroDash transforms a dash into 0.
+func zeroDash(displayed string) (string, error) {
+	trimmed := strings.TrimSpace(displayed)
+	if trimmed == "" || strings.Trim(trimmed, dashes) != "" {
+		return "", errors.New("expected a dash")
+	}
+
+	return "0", nil
+}
+
+// numberTransform returns a TransformFunc that validates a displayed number against pattern,
+// then removes its group separators and replaces decimalSeparator with a '.'.
+func numberTransform(pattern string, decimalSeparator rune) TransformFunc {
+	matcher := regexp.MustCompile(pattern)
+
+	return func(displayed string) (string, error) {
+		trimmed := strings.TrimSpace(displayed)
+		if !matcher.MatchString(trimmed) || !consistentGroupSeparators(trimmed, decimalSeparator) {
+			return "", errors.New("not a number in the expected format")
+		}
+
+		var builder strings.Builder
+		for _, r := range trimmed {
+			switch {
+			case r >= '0' && r <= '9':
+				builder.WriteRune(r)
+			case r == decimalSeparator:
+				builder.WriteRune('.')
+			}
+		}
+
+		return normalizeDecimalString(builder.String()), nil
+	}
+}
+
+// unitDecimal returns a TransformFunc for numbers whose integer and fractional parts are separated by unit names,
+// ie "5 dollars 25 cents". The fractional part is one or two digits, and is optional.
+func unitDecimal(pattern string) TransformFunc {
+	matcher := regexp.MustCompile(pattern)
+
+	return func(displayed string) (string, error) {
+		matches := matcher.FindStringSubmatch(strings.TrimSpace(displayed))
+		if matches == nil || !consistentGroupSeparators(matches[1], 0) {
+			return "", errors.New("not a number in the expected format")
+		}
+
+		integer := strings.Map(func(r rune) rune {
+			if r >= '0' && r <= '9' {
+				return r
+			}
+
+			return -1
+		}, matches[1])
+
+		if matches[2] == "" {
+			return normalizeDecimalString(integer), nil
+		}
+
+		fraction := matches[2]
+		if len(fraction) == 1 {
+			fraction = "0" + fraction
+		}
+
+		return normalizeDecimalString(integer + "." + fraction), nil
+	}
+}
+
+// consistentGroupSeparators returns true if all the digit groups of the integer part of number are separated by the same character,
+// so "1,234,567" and "1 234 567" are accepted but "1,234 567" and "1,234.567" aren't. The integer part ends at decimalSeparator.
+func consistentGroupSeparators(number string, decimalSeparator rune) bool {
+	var separator rune
+	for _, r := range number {
+		switch {
+		case r == decimalSeparator:
+			return true
+		case r >= '0' && r <= '9':
+			continue
+		case separator == 0:
+			separator = r
+		case r != separator:
+			return false
+		}
+	}
+
+	return true
+}
+
+// normalizeDecimalString removes a trailing decimal point and adds a leading zero to numbers like ".5".
+func normalizeDecimalString(value string) string {
+	value = strings.TrimSuffix(value, ".")
+	if strings.HasPrefix(value, ".") {
+		value = "0" + value
+	}
+
+	return value
+}
+
+// Month names by language, lower case. Each map includes full names, common abbreviations and inflected forms.
+var (
+	monthNamesEN = monthNames(
+		[]string{"january", "jan"}, []string{"february", "feb"}, []string{"march", "mar"}, []string{"april", "apr"},
+		[]string{"may"}, []string{"june", "jun"}, []string{"july", "jul"}, []string{"august", "aug"},
+		[]string{"september", "sep", "sept"}, []string{"october", "oct"}, []string{"november", "nov"}, []string{"december", "dec"},
+	)
+	monthNamesDA = monthNames(
+		[]string{"januar", "jan"}, []string{"februar", "feb"}, []string{"marts", "mar"}, []string{"april", "apr"},
+		[]string{"maj"}, []string{"juni", "jun"}, []string{"juli", "jul"}, []string{"august", "aug"},
+		[]string{"september", "sep", "sept"}, []string{"oktober", "okt"}, []string{"november", "nov"}, []string{"december", "dec"},
+	)
+	monthNamesDE = monthNames(
+		[]string{"januar", "jänner", "jan", "jän"}, []string{"februar", "feber", "feb"}, []string{"märz", "mär", "mrz"}, []string{"april", "apr"},
+		[]string{"mai"}, []string{"juni", "jun"}, []string{"juli", "jul"}, []string{"august", "aug"},
+		[]string{"september", "sep", "sept"}, []string{"oktober", "okt"}, []string{"november", "nov"}, []string{"dezember", "dez"},
+	)
+	monthNamesES = monthNames(
+		[]string{"enero", "ene"}, []string{"febrero", "feb"}, []string{"marzo", "mar"}, []string{"abril", "abr"},
+		[]string{"mayo", "may"}, []string{"junio", "jun"}, []string{"julio", "jul"}, []string{"agosto", "ago"},
+		[]string{"septiembre", "setiembre", "sep", "sept", "set"}, []string{"octubre", "oct"}, []string{"noviembre", "nov"}, []string{"diciembre", "dic"},
+	)
+	monthNamesFI = monthNames(
+		[]string{"tammikuu", "tammikuuta", "tammi"}, []string{"helmikuu", "helmikuuta", "helmi"},
+		[]string{"maaliskuu", "maaliskuuta", "maalis"}, []string{"huhtikuu", "huhtikuuta", "huhti"},
+		[]string{"toukokuu", "toukokuuta", "touko"}, []string{"kesäkuu", "kesäkuuta", "kesä"},
+		[]string{"heinäkuu", "heinäkuuta", "heinä"}, []string{"elokuu", "elokuuta", "elo"},
+		[]string{"syyskuu", "syyskuuta", "syys"}, []string{"lokakuu", "lokakuuta", "loka"},
+		[]string{"marraskuu", "marraskuuta", "marras"}, []string{"joulukuu", "joulukuuta", "joulu"},
+	)
+	monthNamesFR = monthNames(
+		[]string{"janvier", "janv", "jan"}, []string{"février", "fevrier", "févr", "fevr", "fév", "fev"}, []string{"mars", "mar"}, []string{"avril", "avr"},
+		[]string{"mai"}, []string{"juin"}, []string{"juillet", "juil"}, []string{"août", "aout", "aoû"},
+		[]string{"septembre", "sept", "sep"}, []string{"octobre", "oct"}, []string{"novembre", "nov"}, []string{"décembre", "decembre", "déc", "dec"},
+	)
+	monthNamesIT = monthNames(
+		[]string{"gennaio", "gen"}, []string{"febbraio", "feb"}, []string{"marzo", "mar"}, []string{"aprile", "apr"},
+		[]string{"maggio", "mag"}, []string{"giugno", "giu"}, []string{"luglio", "lug"}, []string{"agosto", "ago"},
+		[]string{"settembre", "set"}, []string{"ottobre", "ott"}, []string{"novembre", "nov"}, []string{"dicembre", "dic"},
+	)
+	monthNamesNL = monthNames(
+		[]string{"januari", "jan"}, []string{"februari", "feb"}, []string{"maart", "mrt", "mar"}, []string{"april", "apr"},
+		[]string{"mei"}, []string{"juni", "jun"}, []string{"juli", "jul"}, []string{"augustus", "aug"},
+		[]string{"september", "sep", "sept"}, []string{"oktober", "okt"}, []string{"november", "nov"}, []string{"december", "dec"},
+	)
+	monthNamesNO = monthNames(
+		[]string{"januar", "jan"}, []string{"februar", "feb"}, []string{"mars", "mar"}, []string{"april", "apr"},
+		[]string{"mai"}, []string{"juni", "jun"}, []string{"juli", "jul"}, []string{"august", "aug"},
+		[]string{"september", "sep", "sept"}, []string{"oktober", "okt"}, []string{"november", "nov"}, []string{"desember", "des"},
+	)
+	monthNamesPL = monthNames(
+		[]string{"styczeń", "stycznia", "sty"}, []string{"luty", "lutego", "lut"}, []string{"marzec", "marca", "mar"},
+		[]string{"kwiecień", "kwietnia", "kwi"}, []string{"maj", "maja"}, []string{"czerwiec", "czerwca", "cze"},
+		[]string{"lipiec", "lipca", "lip"}, []string{"sierpień", "sierpnia", "sie"}, []string{"wrzesień", "września", "wrz"},
+		[]string{"październik", "października", "paź"}, []string{"listopad", "listopada", "lis"}, []string{"grudzień", "grudnia", "gru"},
+	)
+	monthNamesPT = monthNames(
+		[]string{"janeiro", "jan"}, []string{"fevereiro", "fev"}, []string{"março", "marco", "mar"}, []string{"abril", "abr"},
+		[]string{"maio", "mai"}, []string{"junho", "jun"}, []string{"julho", "jul"}, []string{"agosto", "ago"},
+		[]string{"setembro", "set"}, []string{"outubro", "out"}, []string{"novembro", "nov"}, []string{"dezembro", "dez"},
+	)
+	monthNamesSV = monthNames(
+		[]string{"januari", "jan"}, []string{"februari", "feb"}, []string{"mars", "mar"}, []string{"april", "apr"},
+		[]string{"maj"}, []string{"juni", "jun"}, []string{"juli", "jul"}, []string{"augusti", "aug"},
+		[]string{"september", "sep", "sept"}, []string{"oktober", "okt"}, []string{"november", "nov"}, []string{"december", "dec"},
+	)
+	monthNamesRoman = monthNames(
+		[]string{"i"}, []string{"ii"}, []string{"iii"}, []string{"iv"}, []string{"v"}, []string{"vi"},
+		[]string{"vii"}, []string{"viii"}, []string{"ix"}, []string{"x"}, []string{"xi"}, []string{"xii"},
+	)
+
+	// monthNamesByLanguage are the languages for which the Transformation Registry 4 date-*-monthname-* formats are registered.
+	monthNamesByLanguage = map[string]map[string]time.Month{
+		"da": monthNamesDA,
+		"de": monthNamesDE,
+		"en": monthNamesEN,
+		"es": monthNamesES,
+		"fi": monthNamesFI,
+		"fr": monthNamesFR,
+		"it": monthNamesIT,
+		"nl": monthNamesNL,
+		"no": monthNamesNO,
+		"pl": monthNamesPL,
+		"pt": monthNamesPT,
+		"sv": monthNamesSV,
+	}
+)
+
+// monthNames builds a lookup table from twelve lists of names, in calendar order.
+func monthNames(names ...[]string) map[string]time.Month {
+	lookup := make(map[string]time.Month)
+	for index, monthNames := range names {
+		for _, name := range monthNames {
+			lookup[name] = time.Month(index + 1)
+		}
+	}
+
+	return lookup
+}
+
+// dateFillerWords are words that may appear between the parts of a displayed date, ie ordinal suffixes ("27th") and articles ("27 de marzo").
+var dateFillerWords = map[string]bool{
+	"st": true, "nd": true, "rd": true, "th": true, "of": true, "the": true,
+	"de": true, "del": true, "er": true, "e": true, "o": true, "a": true,
+}
+
+// dateTransform returns a TransformFunc for a date whose day (D), month (M) and year (Y) appear in the given order.
+// If months is nil, the month is expected to be numeric and spaces, punctuation and the CJK year, month and day markers
+// are treated as separators, which handles both "03/27/2021" and CJK dates like "2021年3月27日". Anything else is rejected.
+// Otherwise the month is expected to be one of the names in months.
+//
+// The result is an xsd:date (YYYY-MM-DD), xsd:gMonthDay (--MM-DD) or xsd:gYearMonth (YYYY-MM) depending on the parts in order.
+func dateTransform(order string, months map[string]time.Month) TransformFunc {
+	return func(displayed string) (string, error) {
+		parts, err := dateParts(displayed, months)
+		if err != nil {
+			return "", err
+		}
+
+		if len(parts) != len(order) {
+			return "", fmt.Errorf("expected %d date parts, found %d", len(order), len(parts))
+		}
+
+		year, month, day := 2000, time.January, 1
+		for index, part := range parts {
+			switch order[index] {
+			case 'Y':
+				if part.month != 0 {
+					return "", errors.New("expected a year but found a month name")
+				}
+
+				if year, err = parseYear(part.digits); err != nil {
+					return "", err
+				}
+			case 'M':
+				if months != nil && part.month == 0 {
+					return "", errors.New("expected a month name")
+				}
+
+				month = part.month
+				if months == nil {
+					number, err := strconv.Atoi(part.digits)
+					if err != nil || len(part.digits) > 2 || number < 1 || number > 12 {
+						return "", fmt.Errorf("invalid month: %s", part.digits)
+					}
+
+					month = time.Month(number)
+				}
+			case 'D':
+				if part.month != 0 {
+					return "", errors.New("expected a day but found a month name")
+				}
+
+				if len(part.digits) > 2 {
+					return "", fmt.Errorf("invalid day: %s", part.digits)
+				}
+
+				day, _ = strconv.Atoi(part.digits)
+			}
+		}
+
+		// Without a year use a leap year, so February 29th is accepted.
+		if date := time.Date(year, month, day, 0, 0, 0, 0, time.UTC); date.Day() != day || date.Month() != month {
+			return "", fmt.Errorf("invalid day %d for month %d", day, month)
+		}
+
+		switch {
+		case !strings.ContainsRune(order, 'Y'):
+			return fmt.Sprintf("--%02d-%02d", month, day), nil
+		case !strings.ContainsRune(order, 'D'):
+			return fmt.Sprintf("%04d-%02d", year, month), nil
+		default:
+			return fmt.Sprintf("%04d-%02d-%02d", year, month, day), nil
+		}
+	}
+}
+
+// datePart is either a run of digits, or a month name.
+type datePart struct {
+	digits string
+	month  time.Month
+}
+
+// dateMarkers are the characters that follow the year, month and day of CJK dates.
+const dateMarkers = "年月日년월일"
+
+// dateParts splits a displayed date into runs of digits and, if months is non-nil, month names.
+// Full width digits are converted to ASCII digits.
+func dateParts(displayed string, months map[string]time.Month) ([]datePart, error) {
+	var parts []datePart
+	var digits, word strings.Builder
+
+	flush := func() error {
+		if digits.Len() > 0 {
+			parts = append(parts, datePart{digits: digits.String()})
+			digits.Reset()
+		}
+
+		if word.Len() > 0 {
+			name := strings.ToLower(word.String())
+			word.Reset()
+
+			if month, exists := months[name]; exists {
+				parts = append(parts, datePart{month: month})
+			} else if !dateFillerWords[name] {
+				return fmt.Errorf("unrecognized month name: %s", name)
+			}
+		}
+
+		return nil
+	}
+
+	for _, r := range asciiDigits(displayed) {
+		switch {
+		case r >= '0' && r <= '9':
+			if word.Len() > 0 {
+				if err := flush(); err != nil {
+					return nil, err
+				}
+			}
+
+			digits.WriteRune(r)
+		case months != nil && unicode.IsLetter(r):
+			if digits.Len() > 0 {
+				if err := flush(); err != nil {
+					return nil, err
+				}
+			}
+
+			word.WriteRune(r)
+		case unicode.IsSpace(r) || unicode.IsPunct(r) || strings.ContainsRune(dateMarkers, r):
+			if err := flush(); err != nil {
+				return nil, err
+			}
+		default:
+			return nil, fmt.Errorf("unexpected character %q", r)
+		}
+	}
+
+	if err := flush(); err != nil {
+		return nil, err
+	}
+
+	return parts, nil
+}
+
+// asciiDigits replaces full width digits (common in CJK documents) with ASCII digits.
+func asciiDigits(s string) string {
+	return strings.Map(func(r rune) rune {
+		if r >= '０' && r <= '９' {
+			return '0' + (r - '０')
+		}
+
+		return r
+	}, s)
+}
+
+// parseYear parses a 1, 2 or 4 digit year. Years with fewer than 4 digits are assumed to be in the 21st century.
+func parseYear(digits string) (int, error) {
+	year, err := strconv.Atoi(digits)
+	if err != nil || len(digits) == 3 || len(digits) > 4 {
+		return 0, fmt.Errorf("invalid year: %s", digits)
+	}
+
+	if len(digits) < 4 {
+		year += 2000
+	}
+
+	return year, nil
+}
+
+// japaneseEras maps the names of Japanese eras to the Gregorian year before the era's first year.
+var japaneseEras = map[string]int{
+	"明治": 1867,
+	"大正": 1911,
+	"昭和": 1925,
+	"平成": 1988,
+	"令和": 2018,
+}
+
+// japaneseEraDate returns a TransformFunc for dates in the Japanese era calendar, ie "平成23年3月31日" or "令和元年5月".
+func japaneseEraDate(withDay bool) TransformFunc {
+	order := "YM"
+	if withDay {
+		order = "YMD"
+	}
+
+	gregorian := dateTransform(order, nil)
+
+	return func(displayed string) (string, error) {
+		trimmed := strings.TrimSpace(asciiDigits(displayed))
+
+		for era, offset := range japaneseEras {
+			if !strings.HasPrefix(trimmed, era) {
+				continue
+			}
+
+			rest := strings.TrimSpace(strings.TrimPrefix(trimmed, era))
+
+			// The first year of an era is written as 元 (gannen).
+			year := 1
+			if strings.HasPrefix(rest, "元") {
+				rest = strings.TrimPrefix(rest, "元")
+			} else {
+				parts, err := dateParts(rest, nil)
+				if err != nil || len(parts) == 0 {
+					return "", errors.New("missing era year")
+				}
+
+				var convErr error
+				if year, convErr = strconv.Atoi(parts[0].digits); convErr != nil || year < 1 {
+					return "", fmt.Errorf("invalid era year: %s", parts[0].digits)
+				}
+
+				rest = rest[strings.Index(rest, parts[0].digits)+len(parts[0].digits):]
+			}
+
+			return gregorian(fmt.Sprintf("%04d %s", offset+year, rest))
+		}
+
+		return "", errors.New("missing Japanese era name")
+	}
+}
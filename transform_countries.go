@@ -0,0 +1,311 @@
+package xbrl
+
+// countryCodesEN maps the English names of countries to their ISO 3166-1 alpha-2 codes. It has every country in ISO 3166-1,
+// by its short name, its official name and its common name, with and without diacritics, as well as a few other names they're known by.
+// https://www.iso.org/iso-3166-country-codes.html
+var countryCodesEN = map[string]string{
+	"andorra": "AD", "principality of andorra": "AD",
+	"united arab emirates": "AE",
+	"afghanistan":          "AF", "islamic republic of afghanistan": "AF",
+	"antigua and barbuda": "AG",
+	"anguilla":            "AI",
+	"albania":             "AL", "republic of albania": "AL",
+	"armenia": "AM", "republic of armenia": "AM",
+	"angola": "AO", "republic of angola": "AO",
+	"antarctica": "AQ",
+	"argentina":  "AR", "argentine republic": "AR",
+	"american samoa": "AS",
+	"austria":        "AT", "republic of austria": "AT",
+	"australia":     "AU",
+	"aruba":         "AW",
+	"åland islands": "AX", "aland islands": "AX",
+	"azerbaijan": "AZ", "republic of azerbaijan": "AZ",
+	"bosnia and herzegovina": "BA", "republic of bosnia and herzegovina": "BA",
+	"barbados":   "BB",
+	"bangladesh": "BD", "people's republic of bangladesh": "BD",
+	"belgium": "BE", "kingdom of belgium": "BE",
+	"burkina faso": "BF",
+	"bulgaria":     "BG", "republic of bulgaria": "BG",
+	"bahrain": "BH", "kingdom of bahrain": "BH",
+	"burundi": "BI", "republic of burundi": "BI",
+	"benin": "BJ", "republic of benin": "BJ",
+	"saint barthélemy": "BL", "saint barthelemy": "BL",
+	"bermuda":           "BM",
+	"brunei darussalam": "BN", "brunei": "BN",
+	"bolivia, plurinational state of": "BO", "plurinational state of bolivia": "BO", "bolivia": "BO",
+	"bonaire, sint eustatius and saba": "BQ", "bonaire": "BQ",
+	"brazil": "BR", "federative republic of brazil": "BR",
+	"bahamas": "BS", "commonwealth of the bahamas": "BS",
+	"bhutan": "BT", "kingdom of bhutan": "BT",
+	"bouvet island": "BV",
+	"botswana":      "BW", "republic of botswana": "BW",
+	"belarus": "BY", "republic of belarus": "BY",
+	"belize":                  "BZ",
+	"canada":                  "CA",
+	"cocos (keeling) islands": "CC", "cocos islands": "CC",
+	"congo, the democratic republic of the": "CD", "democratic republic of the congo": "CD",
+	"central african republic": "CF",
+	"congo":                    "CG", "republic of the congo": "CG",
+	"switzerland": "CH", "swiss confederation": "CH",
+	"côte d'ivoire": "CI", "cote d'ivoire": "CI", "republic of côte d'ivoire": "CI", "republic of cote d'ivoire": "CI", "ivory coast": "CI",
+	"cook islands": "CK",
+	"chile":        "CL", "republic of chile": "CL",
+	"cameroon": "CM", "republic of cameroon": "CM",
+	"china": "CN", "people's republic of china": "CN",
+	"colombia": "CO", "republic of colombia": "CO",
+	"costa rica": "CR", "republic of costa rica": "CR",
+	"cuba": "CU", "republic of cuba": "CU",
+	"cabo verde": "CV", "republic of cabo verde": "CV", "cape verde": "CV",
+	"curaçao": "CW", "curacao": "CW",
+	"christmas island": "CX",
+	"cyprus":           "CY", "republic of cyprus": "CY",
+	"czechia": "CZ", "czech republic": "CZ",
+	"germany": "DE", "federal republic of germany": "DE",
+	"djibouti": "DJ", "republic of djibouti": "DJ",
+	"denmark": "DK", "kingdom of denmark": "DK",
+	"dominica": "DM", "commonwealth of dominica": "DM",
+	"dominican republic": "DO",
+	"algeria":            "DZ", "people's democratic republic of algeria": "DZ",
+	"ecuador": "EC", "republic of ecuador": "EC",
+	"estonia": "EE", "republic of estonia": "EE",
+	"egypt": "EG", "arab republic of egypt": "EG",
+	"western sahara": "EH",
+	"eritrea":        "ER", "state of eritrea": "ER",
+	"spain": "ES", "kingdom of spain": "ES",
+	"ethiopia": "ET", "federal democratic republic of ethiopia": "ET",
+	"finland": "FI", "republic of finland": "FI",
+	"fiji": "FJ", "republic of fiji": "FJ",
+	"falkland islands (malvinas)": "FK", "falkland islands": "FK",
+	"micronesia, federated states of": "FM", "federated states of micronesia": "FM", "micronesia": "FM",
+	"faroe islands": "FO",
+	"france":        "FR", "french republic": "FR",
+	"gabon": "GA", "gabonese republic": "GA",
+	"united kingdom": "GB", "united kingdom of great britain and northern ireland": "GB", "uk": "GB", "great britain": "GB",
+	"grenada": "GD",
+	"georgia": "GE", "republic of georgia": "GE",
+	"french guiana": "GF",
+	"guernsey":      "GG",
+	"ghana":         "GH", "republic of ghana": "GH",
+	"gibraltar": "GI",
+	"greenland": "GL",
+	"gambia":    "GM", "republic of the gambia": "GM",
+	"guinea": "GN", "republic of guinea": "GN",
+	"guadeloupe":        "GP",
+	"equatorial guinea": "GQ", "republic of equatorial guinea": "GQ",
+	"greece": "GR", "hellenic republic": "GR",
+	"south georgia and the south sandwich islands": "GS",
+	"guatemala": "GT", "republic of guatemala": "GT",
+	"guam":          "GU",
+	"guinea-bissau": "GW", "republic of guinea-bissau": "GW",
+	"guyana": "GY", "republic of guyana": "GY",
+	"hong kong": "HK", "hong kong special administrative region of china": "HK",
+	"heard island and mcdonald islands": "HM",
+	"honduras":                          "HN", "republic of honduras": "HN",
+	"croatia": "HR", "republic of croatia": "HR",
+	"haiti": "HT", "republic of haiti": "HT",
+	"hungary":   "HU",
+	"indonesia": "ID", "republic of indonesia": "ID",
+	"ireland": "IE", "republic of ireland": "IE",
+	"israel": "IL", "state of israel": "IL",
+	"isle of man": "IM",
+	"india":       "IN", "republic of india": "IN",
+	"british indian ocean territory": "IO",
+	"iraq":                           "IQ", "republic of iraq": "IQ",
+	"iran, islamic republic of": "IR", "islamic republic of iran": "IR", "iran": "IR",
+	"iceland": "IS", "republic of iceland": "IS",
+	"italy": "IT", "italian republic": "IT",
+	"jersey":  "JE",
+	"jamaica": "JM",
+	"jordan":  "JO", "hashemite kingdom of jordan": "JO",
+	"japan": "JP",
+	"kenya": "KE", "republic of kenya": "KE",
+	"kyrgyzstan": "KG", "kyrgyz republic": "KG",
+	"cambodia": "KH", "kingdom of cambodia": "KH",
+	"kiribati": "KI", "republic of kiribati": "KI",
+	"comoros": "KM", "union of the comoros": "KM",
+	"saint kitts and nevis":                  "KN",
+	"korea, democratic people's republic of": "KP", "democratic people's republic of korea": "KP", "north korea": "KP",
+	"korea, republic of": "KR", "south korea": "KR", "republic of korea": "KR",
+	"kuwait": "KW", "state of kuwait": "KW",
+	"cayman islands": "KY",
+	"kazakhstan":     "KZ", "republic of kazakhstan": "KZ",
+	"lao people's democratic republic": "LA", "laos": "LA",
+	"lebanon": "LB", "lebanese republic": "LB",
+	"saint lucia":   "LC",
+	"liechtenstein": "LI", "principality of liechtenstein": "LI",
+	"sri lanka": "LK", "democratic socialist republic of sri lanka": "LK",
+	"liberia": "LR", "republic of liberia": "LR",
+	"lesotho": "LS", "kingdom of lesotho": "LS",
+	"lithuania": "LT", "republic of lithuania": "LT",
+	"luxembourg": "LU", "grand duchy of luxembourg": "LU",
+	"latvia": "LV", "republic of latvia": "LV",
+	"libya":   "LY",
+	"morocco": "MA", "kingdom of morocco": "MA",
+	"monaco": "MC", "principality of monaco": "MC",
+	"moldova, republic of": "MD", "republic of moldova": "MD", "moldova": "MD",
+	"montenegro":                 "ME",
+	"saint martin (french part)": "MF", "saint martin": "MF",
+	"madagascar": "MG", "republic of madagascar": "MG",
+	"marshall islands": "MH", "republic of the marshall islands": "MH",
+	"north macedonia": "MK", "republic of north macedonia": "MK", "macedonia": "MK",
+	"mali": "ML", "republic of mali": "ML",
+	"myanmar": "MM", "republic of myanmar": "MM", "burma": "MM",
+	"mongolia": "MN",
+	"macao":    "MO", "macao special administrative region of china": "MO", "macau": "MO",
+	"northern mariana islands": "MP", "commonwealth of the northern mariana islands": "MP",
+	"martinique": "MQ",
+	"mauritania": "MR", "islamic republic of mauritania": "MR",
+	"montserrat": "MS",
+	"malta":      "MT", "republic of malta": "MT",
+	"mauritius": "MU", "republic of mauritius": "MU",
+	"maldives": "MV", "republic of maldives": "MV",
+	"malawi": "MW", "republic of malawi": "MW",
+	"mexico": "MX", "united mexican states": "MX",
+	"malaysia":   "MY",
+	"mozambique": "MZ", "republic of mozambique": "MZ",
+	"namibia": "NA", "republic of namibia": "NA",
+	"new caledonia": "NC",
+	"niger":         "NE", "republic of the niger": "NE",
+	"norfolk island": "NF",
+	"nigeria":        "NG", "federal republic of nigeria": "NG",
+	"nicaragua": "NI", "republic of nicaragua": "NI",
+	"netherlands": "NL", "kingdom of the netherlands": "NL",
+	"norway": "NO", "kingdom of norway": "NO",
+	"nepal": "NP", "federal democratic republic of nepal": "NP",
+	"nauru": "NR", "republic of nauru": "NR",
+	"niue":        "NU",
+	"new zealand": "NZ",
+	"oman":        "OM", "sultanate of oman": "OM",
+	"panama": "PA", "republic of panama": "PA",
+	"peru": "PE", "republic of peru": "PE",
+	"french polynesia": "PF",
+	"papua new guinea": "PG", "independent state of papua new guinea": "PG",
+	"philippines": "PH", "republic of the philippines": "PH",
+	"pakistan": "PK", "islamic republic of pakistan": "PK",
+	"poland": "PL", "republic of poland": "PL",
+	"saint pierre and miquelon": "PM",
+	"pitcairn":                  "PN",
+	"puerto rico":               "PR",
+	"palestine, state of":       "PS", "state of palestine": "PS", "palestine": "PS",
+	"portugal": "PT", "portuguese republic": "PT",
+	"palau": "PW", "republic of palau": "PW",
+	"paraguay": "PY", "republic of paraguay": "PY",
+	"qatar": "QA", "state of qatar": "QA",
+	"réunion": "RE", "reunion": "RE",
+	"romania": "RO",
+	"serbia":  "RS", "republic of serbia": "RS",
+	"russian federation": "RU", "russia": "RU",
+	"rwanda": "RW", "rwandese republic": "RW",
+	"saudi arabia": "SA", "kingdom of saudi arabia": "SA",
+	"solomon islands": "SB",
+	"seychelles":      "SC", "republic of seychelles": "SC",
+	"sudan": "SD", "republic of the sudan": "SD",
+	"sweden": "SE", "kingdom of sweden": "SE",
+	"singapore": "SG", "republic of singapore": "SG",
+	"saint helena, ascension and tristan da cunha": "SH", "saint helena": "SH",
+	"slovenia": "SI", "republic of slovenia": "SI",
+	"svalbard and jan mayen": "SJ",
+	"slovakia":               "SK", "slovak republic": "SK",
+	"sierra leone": "SL", "republic of sierra leone": "SL",
+	"san marino": "SM", "republic of san marino": "SM",
+	"senegal": "SN", "republic of senegal": "SN",
+	"somalia": "SO", "federal republic of somalia": "SO",
+	"suriname": "SR", "republic of suriname": "SR",
+	"south sudan": "SS", "republic of south sudan": "SS",
+	"sao tome and principe": "ST", "democratic republic of sao tome and principe": "ST",
+	"el salvador": "SV", "republic of el salvador": "SV",
+	"sint maarten (dutch part)": "SX", "sint maarten": "SX",
+	"syrian arab republic": "SY", "syria": "SY",
+	"eswatini": "SZ", "kingdom of eswatini": "SZ", "swaziland": "SZ",
+	"turks and caicos islands": "TC",
+	"chad":                     "TD", "republic of chad": "TD",
+	"french southern territories": "TF",
+	"togo":                        "TG", "togolese republic": "TG",
+	"thailand": "TH", "kingdom of thailand": "TH",
+	"tajikistan": "TJ", "republic of tajikistan": "TJ",
+	"tokelau":     "TK",
+	"timor-leste": "TL", "democratic republic of timor-leste": "TL", "east timor": "TL",
+	"turkmenistan": "TM",
+	"tunisia":      "TN", "republic of tunisia": "TN",
+	"tonga": "TO", "kingdom of tonga": "TO",
+	"türkiye": "TR", "turkiye": "TR", "republic of türkiye": "TR", "republic of turkiye": "TR", "turkey": "TR",
+	"trinidad and tobago": "TT", "republic of trinidad and tobago": "TT",
+	"tuvalu":                    "TV",
+	"taiwan, province of china": "TW", "taiwan": "TW",
+	"tanzania, united republic of": "TZ", "united republic of tanzania": "TZ", "tanzania": "TZ",
+	"ukraine": "UA",
+	"uganda":  "UG", "republic of uganda": "UG",
+	"united states minor outlying islands": "UM",
+	"united states":                        "US", "united states of america": "US", "usa": "US", "u.s.a.": "US", "u.s.": "US",
+	"uruguay": "UY", "eastern republic of uruguay": "UY",
+	"uzbekistan": "UZ", "republic of uzbekistan": "UZ",
+	"holy see (vatican city state)": "VA", "vatican city": "VA", "vatican": "VA", "holy see": "VA",
+	"saint vincent and the grenadines":  "VC",
+	"venezuela, bolivarian republic of": "VE", "bolivarian republic of venezuela": "VE", "venezuela": "VE",
+	"virgin islands, british": "VG", "british virgin islands": "VG",
+	"virgin islands, u.s.": "VI", "virgin islands of the united states": "VI", "u.s. virgin islands": "VI", "united states virgin islands": "VI",
+	"viet nam": "VN", "socialist republic of viet nam": "VN", "vietnam": "VN",
+	"vanuatu": "VU", "republic of vanuatu": "VU",
+	"wallis and futuna": "WF",
+	"samoa":             "WS", "independent state of samoa": "WS",
+	"yemen": "YE", "republic of yemen": "YE",
+	"mayotte":      "YT",
+	"south africa": "ZA", "republic of south africa": "ZA",
+	"zambia": "ZM", "republic of zambia": "ZM",
+	"zimbabwe": "ZW", "republic of zimbabwe": "ZW",
+}
+
+// edgarCountryCodesByISO maps ISO 3166-1 alpha-2 codes to the EDGAR codes of the same countries.
+// EDGAR has no code for the United States, whose states have their own, nor for Bonaire, Curaçao, Sint Maarten and South Sudan.
+// https://www.sec.gov/edgar/searchedgar/edgarstatecodes.htm
+var edgarCountryCodesByISO = map[string]string{
+	"AD": "B6", "AE": "C0", "AF": "B2", "AG": "B9", "AI": "1A", "AL": "B3", "AM": "1B", "AO": "B7", "AQ": "AQ", "AR": "C1",
+	"AS": "B5", "AT": "C4", "AU": "C3", "AW": "1C", "AX": "Y6", "AZ": "1D", "BA": "1E", "BB": "C8", "BD": "C7", "BE": "C9",
+	"BF": "X2", "BG": "E0", "BH": "C6", "BI": "E2", "BJ": "G6", "BL": "Z0", "BM": "D0", "BN": "D9", "BO": "D3", "BR": "D5",
+	"BS": "C5", "BT": "D2", "BV": "D4", "BW": "B1", "BY": "1F", "BZ": "D1", "CA": "Z4", "CC": "F7", "CD": "Y3", "CF": "F0",
+	"CG": "G0", "CH": "V8", "CI": "L7", "CK": "G1", "CL": "F3", "CM": "E4", "CN": "F4", "CO": "F8", "CR": "G2", "CU": "G3",
+	"CV": "E8", "CX": "F6", "CY": "G4", "CZ": "2N", "DE": "2M", "DJ": "1G", "DK": "G7", "DM": "G9", "DO": "G8", "DZ": "B4",
+	"EC": "H1", "EE": "1H", "EG": "H2", "EH": "U5", "ER": "1J", "ES": "U3", "ET": "H5", "FI": "H9", "FJ": "H8", "FK": "H7",
+	"FM": "1K", "FO": "H6", "FR": "I0", "GA": "I5", "GB": "X0", "GD": "J5", "GE": "2Q", "GF": "I3", "GG": "Y7", "GH": "J0",
+	"GI": "J1", "GL": "J4", "GM": "I6", "GN": "J9", "GP": "J6", "GQ": "H4", "GR": "J3", "GS": "1L", "GT": "J8", "GU": "GU",
+	"GW": "S0", "GY": "K0", "HK": "K3", "HM": "K4", "HN": "K2", "HR": "1M", "HT": "K1", "HU": "K5", "ID": "K8", "IE": "L2",
+	"IL": "L3", "IM": "Y8", "IN": "K7", "IO": "D6", "IQ": "L0", "IR": "K9", "IS": "K6", "IT": "L6", "JE": "Y9", "JM": "L8",
+	"JO": "M2", "JP": "M0", "KE": "M3", "KG": "1N", "KH": "E3", "KI": "J2", "KM": "F9", "KN": "U7", "KP": "M4", "KR": "M5",
+	"KW": "M6", "KY": "E9", "KZ": "1P", "LA": "M7", "LB": "M8", "LC": "U9", "LI": "N2", "LK": "F1", "LR": "N0", "LS": "M9",
+	"LT": "1Q", "LU": "N4", "LV": "1R", "LY": "N1", "MA": "P2", "MC": "O9", "MD": "1S", "ME": "Z5", "MF": "Z1", "MG": "N6",
+	"MH": "1T", "MK": "1U", "ML": "O0", "MM": "E1", "MN": "P0", "MO": "N5", "MP": "1V", "MQ": "O2", "MR": "O3", "MS": "P1",
+	"MT": "O1", "MU": "O4", "MV": "N9", "MW": "N7", "MX": "O5", "MY": "N8", "MZ": "P3", "NA": "T6", "NC": "1W", "NE": "Q4",
+	"NF": "Q7", "NG": "Q5", "NI": "Q3", "NL": "P7", "NO": "Q8", "NP": "P6", "NR": "P5", "NU": "Q6", "NZ": "Q2", "OM": "P4",
+	"PA": "R1", "PE": "R5", "PF": "I4", "PG": "R2", "PH": "R6", "PK": "R0", "PL": "R9", "PM": "V0", "PN": "R8", "PR": "PR",
+	"PS": "1X", "PT": "S1", "PW": "1Y", "PY": "R4", "QA": "S3", "RE": "S4", "RO": "S5", "RS": "Z2", "RU": "1Z", "RW": "S6",
+	"SA": "T0", "SB": "D7", "SC": "T2", "SD": "V2", "SE": "V7", "SG": "U0", "SH": "U8", "SI": "2A", "SJ": "L9", "SK": "2B",
+	"SL": "T8", "SM": "S8", "SN": "T1", "SO": "U1", "SR": "V3", "ST": "S9", "SV": "H3", "SY": "V9", "SZ": "V6", "TC": "W7",
+	"TD": "F2", "TF": "2C", "TG": "W2", "TH": "W1", "TJ": "2D", "TK": "W3", "TL": "Z3", "TM": "2E", "TN": "W6", "TO": "W4",
+	"TR": "W8", "TT": "W5", "TV": "2G", "TW": "F5", "TZ": "W0", "UA": "2H", "UG": "W9", "UM": "2J", "UY": "X3", "UZ": "2K",
+	"VA": "X4", "VC": "V1", "VE": "X5", "VG": "D8", "VI": "VI", "VN": "Q1", "VU": "2L", "WF": "X8", "WS": "Y0", "YE": "T7",
+	"YT": "2P", "ZA": "T3", "ZM": "Y4", "ZW": "Y5",
+}
+
+// edgarCountryCodes maps the names of Canadian provinces and of countries to the EDGAR codes used for states and countries outside the US.
+// Countries are known by all their names in countryCodesEN, except for Georgia, which is the US state in EDGAR.
+var edgarCountryCodes = edgarCountryNames()
+
+// edgarCountryNames returns the table of edgarCountryCodes.
+func edgarCountryNames() map[string]string {
+	names := map[string]string{
+		"alberta, canada": "A0", "british columbia, canada": "A1", "manitoba, canada": "A2", "new brunswick, canada": "A3",
+		"newfoundland, canada": "A4", "nova scotia, canada": "A5", "ontario, canada": "A6", "prince edward island, canada": "A7",
+		"quebec, canada": "A8", "saskatchewan, canada": "A9", "yukon, canada": "B0", "canada (federal level)": "Z4",
+		"alberta": "A0", "british columbia": "A1", "manitoba": "A2", "new brunswick": "A3", "newfoundland": "A4",
+		"nova scotia": "A5", "ontario": "A6", "prince edward island": "A7", "quebec": "A8", "saskatchewan": "A9", "yukon": "B0",
+		"netherlands antilles": "P8",
+	}
+
+	for name, iso := range countryCodesEN {
+		if code, exists := edgarCountryCodesByISO[iso]; exists && name != "georgia" {
+			names[name] = code
+		}
+	}
+
+	return names
+}
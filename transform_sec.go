@@ -0,0 +1,415 @@
+package xbrl
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"math/big"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+func registerSECTransforms(r TransformRegistry) {
+	ns := NamespaceTransformationSEC
+
+	r.Register(ns, "numwordsen", numWordsEN)
+	r.Register(ns, "numinf", fixedValue("INF"))
+	r.Register(ns, "numneginf", fixedValue("-INF"))
+	r.Register(ns, "numnan", fixedValue("NaN"))
+
+	r.Register(ns, "duryear", durationTransform(durationYears))
+	r.Register(ns, "durmonth", durationTransform(durationMonths))
+	r.Register(ns, "durweek", durationTransform(durationWeeks))
+	r.Register(ns, "durday", durationTransform(durationDays))
+	r.Register(ns, "durhour", durationTransform(durationHours))
+	r.Register(ns, "durwordsen", durationWordsEN)
+
+	r.Register(ns, "datequarterend", dateQuarterEnd)
+
+	r.Register(ns, "boolballotbox", ballotBox("false", "true"))
+	r.Register(ns, "yesnoballotbox", ballotBox("No", "Yes"))
+
+	r.Register(ns, "countrynameen", lookupTransform(countryCodesEN))
+	r.Register(ns, "stateprovnameen", lookupTransform(stateProvinceCodesEN))
+	r.Register(ns, "exchnameen", lookupTransform(exchangeCodesEN))
+	r.Register(ns, "entityfilercategoryen", lookupTransform(entityFilerCategoriesEN))
+	r.Register(ns, "edgarprovcountryen", lookupTransform(edgarStateCountryCodesEN))
+}
+
+// numberWords are the English words for numbers, used by ixt-sec:numwordsen and ixt-sec:durwordsen.
+var numberWords = map[string]int64{
+	"zero": 0, "no": 0, "none": 0,
+	"one": 1, "a": 1, "an": 1, "two": 2, "three": 3, "four": 4, "five": 5, "six": 6, "seven": 7, "eight": 8, "nine": 9,
+	"ten": 10, "eleven": 11, "twelve": 12, "thirteen": 13, "fourteen": 14, "fifteen": 15,
+	"sixteen": 16, "seventeen": 17, "eighteen": 18, "nineteen": 19,
+	"twenty": 20, "thirty": 30, "forty": 40, "fifty": 50, "sixty": 60, "seventy": 70, "eighty": 80, "ninety": 90,
+}
+
+// numberScales are the English words that multiply the number before them.
+var numberScales = map[string]int64{
+	"hundred":  100,
+	"thousand": 1000,
+	"million":  1000000,
+	"billion":  1000000000,
+	"trillion": 1000000000000,
+}
+
+// numWordsEN transforms a number written in English words, ie "one hundred and twenty-three", into 123.
+func numWordsEN(displayed string) (string, error) {
+	value, err := parseNumberWords(strings.Fields(strings.ToLower(strings.NewReplacer("-", " ", ",", " ").Replace(displayed))))
+	if err != nil {
+		return "", err
+	}
+
+	return value.String(), nil
+}
+
+// parseNumberWords parses a sequence of English number words into an integer.
+// The words must be in the order they're spoken in: "twenty one" is 21, but "one two" and "twelve one" aren't numbers.
+// Scale words go from large to small, except that one can directly follow a smaller one to scale it further,
+// ie "one hundred thousand million" is 10^11. So there's no upper limit, which is why the result is a big.Int.
+func parseNumberWords(words []string) (*big.Int, error) {
+	if len(words) == 0 {
+		return nil, errors.New("expected a number in words")
+	}
+
+	// previous is the value of the last word if it was a number word, or -1 if it wasn't.
+	// scaled is the value of the last word if it was a scale word, or 0 if it wasn't.
+	// largest is the largest scale word that has been added to total so far.
+	total, current := new(big.Int), new(big.Int)
+	previous, scaled, largest := int64(-1), int64(0), int64(0)
+	for index, word := range words {
+		if word == "and" {
+			continue
+		}
+
+		if value, exists := numberWords[word]; exists {
+			// Only a units word can follow another number word, and only if that's a multiple of ten, ie "twenty one".
+			if previous >= 0 && (value >= 10 || previous < 20 || previous%10 != 0) {
+				return nil, fmt.Errorf("unexpected number word: %s", word)
+			}
+
+			current.Add(current, big.NewInt(value))
+			previous, scaled = value, 0
+			continue
+		}
+
+		scale, exists := numberScales[word]
+		if !exists {
+			return nil, fmt.Errorf("not a number word: %s", word)
+		}
+
+		switch {
+		case scaled > 100 && scale > largest:
+			// The scale applies to everything before it, ie "thousand million".
+			total.Mul(total, big.NewInt(scale))
+			largest = scale
+		case scaled > 100 || (scaled == 100 && scale == 100) || (scaled == 0 && previous < 0 && index > 0):
+			return nil, fmt.Errorf("unexpected scale word: %s", word)
+		case scale == 100:
+			if current.Sign() == 0 {
+				current.SetInt64(1)
+			}
+
+			current.Mul(current, big.NewInt(scale))
+		case largest != 0 && scale >= largest:
+			return nil, fmt.Errorf("unexpected scale word: %s", word)
+		default:
+			if current.Sign() == 0 {
+				current.SetInt64(1)
+			}
+
+			total.Add(total, current.Mul(current, big.NewInt(scale)))
+			current.SetInt64(0)
+			if largest == 0 {
+				largest = scale
+			}
+		}
+
+		previous, scaled = -1, scale
+	}
+
+	return total.Add(total, current), nil
+}
+
+// Units of the numeric duration transformations.
+const (
+	durationYears = iota
+	durationMonths
+	durationWeeks
+	durationDays
+	durationHours
+)
+
+// daysPerMonth is the average length of a month, used to express fractions of a month in days.
+const daysPerMonth = 30.4375
+
+// durationTransform returns a TransformFunc that converts a (possibly fractional) number of units into an xsd:duration,
+// ie 1.5 years becomes P1Y6M. Fractions smaller than the smallest component of the result are truncated.
+func durationTransform(unit int) TransformFunc {
+	return func(displayed string) (string, error) {
+		trimmed := strings.TrimSpace(displayed)
+
+		value, err := strconv.ParseFloat(strings.ReplaceAll(trimmed, ",", ""), 64)
+		if err != nil || math.IsInf(value, 0) || math.IsNaN(value) {
+			return "", errors.New("expected a decimal number")
+		}
+
+		negative := value < 0
+		value = math.Abs(value)
+
+		var years, months, days, hours, minutes float64
+		switch unit {
+		case durationYears:
+			years, value = math.Modf(value)
+			months, value = math.Modf(value * 12)
+			days = math.Floor(value * daysPerMonth)
+		case durationMonths:
+			months, value = math.Modf(value)
+			days = math.Floor(value * daysPerMonth)
+		case durationWeeks:
+			days, value = math.Modf(value * 7)
+			hours = math.Floor(value * 24)
+		case durationDays:
+			days, value = math.Modf(value)
+			hours, value = math.Modf(value * 24)
+			minutes = math.Floor(value * 60)
+		case durationHours:
+			hours, value = math.Modf(value)
+			minutes = math.Floor(value * 60)
+		}
+
+		return formatDuration(negative, int64(years), int64(months), int64(days), int64(hours), int64(minutes)), nil
+	}
+}
+
+// formatDuration formats the components of a duration as an xsd:duration, omitting components that are zero.
+func formatDuration(negative bool, years, months, days, hours, minutes int64) string {
+	var builder strings.Builder
+	if negative {
+		builder.WriteRune('-')
+	}
+
+	builder.WriteRune('P')
+	for _, component := range []struct {
+		value      int64
+		designator string
+	}{{years, "Y"}, {months, "M"}, {days, "D"}} {
+		if component.value != 0 {
+			builder.WriteString(strconv.FormatInt(component.value, 10) + component.designator)
+		}
+	}
+
+	if hours != 0 || minutes != 0 {
+		builder.WriteRune('T')
+		if hours != 0 {
+			builder.WriteString(strconv.FormatInt(hours, 10) + "H")
+		}
+
+		if minutes != 0 {
+			builder.WriteString(strconv.FormatInt(minutes, 10) + "M")
+		}
+	}
+
+	// A duration needs at least one component.
+	if builder.Len() == 1 || (negative && builder.Len() == 2) {
+		builder.WriteString("0D")
+	}
+
+	return builder.String()
+}
+
+// durationWordPattern matches a number (digits or words) followed by a duration unit, ie "2 years" or "thirty-six months".
+var durationWordPattern = regexp.MustCompile(`(?i)([0-9]+|[a-z][a-z\- ]*?)\s+(years?|months?|weeks?|days?)\b`)
+
+// durationWordsEN transforms a duration written in English, ie "two years and six months", into an xsd:duration (P2Y6M).
+func durationWordsEN(displayed string) (string, error) {
+	matches := durationWordPattern.FindAllStringSubmatch(displayed, -1)
+	if matches == nil {
+		if value, err := numWordsEN(displayed); err == nil && value == "0" {
+			return "P0D", nil
+		}
+
+		return "", errors.New("expected a duration in words")
+	}
+
+	var years, months, days int64
+	for _, match := range matches {
+		var count int64
+		if number, err := strconv.ParseInt(match[1], 10, 32); err == nil {
+			count = number
+		} else {
+			words := strings.Fields(strings.ToLower(strings.ReplaceAll(match[1], "-", " ")))
+			// The pattern can include a leading "and" from a list like "two years and six months".
+			if len(words) > 0 && words[0] == "and" {
+				words = words[1:]
+			}
+
+			number, err := parseNumberWords(words)
+			if err != nil {
+				return "", err
+			}
+
+			// Counts are kept well below the int64 limit, so that adding them up can't overflow.
+			if number.Cmp(big.NewInt(math.MaxInt32)) > 0 {
+				return "", fmt.Errorf("duration too long: %s", strings.TrimSpace(match[1]))
+			}
+
+			count = number.Int64()
+		}
+
+		switch strings.TrimSuffix(strings.ToLower(match[2]), "s") {
+		case "year":
+			years += count
+		case "month":
+			months += count
+		case "week":
+			days += count * 7
+		case "day":
+			days += count
+		}
+	}
+
+	return formatDuration(false, years, months, days, 0, 0), nil
+}
+
+// quarterPatterns match the ways a fiscal quarter is commonly written, capturing the quarter and the year.
+var quarterPatterns = []*regexp.Regexp{
+	regexp.MustCompile(`(?i)^q([1-4])[\s,]+(?:of\s+)?([0-9]{2}|[0-9]{4})$`),
+	regexp.MustCompile(`(?i)^([1-4])(?:st|nd|rd|th)?\s*q(?:uarter|tr)?[\s,]+(?:of\s+)?([0-9]{2}|[0-9]{4})$`),
+	regexp.MustCompile(`(?i)^(first|second|third|fourth)\s+quarter[\s,]+(?:of\s+)?([0-9]{2}|[0-9]{4})$`),
+}
+
+// quarterOrdinals maps the words for quarters to their number.
+var quarterOrdinals = map[string]int{"first": 1, "second": 2, "third": 3, "fourth": 4}
+
+// dateQuarterEnd transforms a calendar quarter, ie "Q1 2021" or "first quarter of 2021", into the date it ends on.
+func dateQuarterEnd(displayed string) (string, error) {
+	trimmed := strings.TrimSpace(displayed)
+
+	for _, pattern := range quarterPatterns {
+		matches := pattern.FindStringSubmatch(trimmed)
+		if matches == nil {
+			continue
+		}
+
+		quarter, exists := quarterOrdinals[strings.ToLower(matches[1])]
+		if !exists {
+			quarter, _ = strconv.Atoi(matches[1])
+		}
+
+		year, err := parseYear(matches[2])
+		if err != nil {
+			return "", err
+		}
+
+		return fmt.Sprintf("%04d-%s", year, [...]string{"03-31", "06-30", "09-30", "12-31"}[quarter-1]), nil
+	}
+
+	return "", errors.New("expected a calendar quarter")
+}
+
+// ballotBox returns a TransformFunc that converts an empty ballot box (☐) to unchecked and a checked one (☑ or ☒) to checked.
+func ballotBox(unchecked, checked string) TransformFunc {
+	return func(displayed string) (string, error) {
+		switch strings.TrimSpace(displayed) {
+		case "☐":
+			return unchecked, nil
+		case "☑", "☒":
+			return checked, nil
+		default:
+			return "", errors.New("expected a ballot box")
+		}
+	}
+}
+
+// lookupTransform returns a TransformFunc that looks up a displayed name in a table of codes.
+// Names are matched case-insensitively, ignoring leading "the" and repeated whitespace.
+func lookupTransform(codes map[string]string) TransformFunc {
+	return func(displayed string) (string, error) {
+		name := strings.Join(strings.Fields(strings.ToLower(displayed)), " ")
+		if code, exists := codes[name]; exists {
+			return code, nil
+		}
+
+		if code, exists := codes[strings.TrimPrefix(name, "the ")]; exists {
+			return code, nil
+		}
+
+		return "", fmt.Errorf("unrecognized name: %s", strings.TrimSpace(displayed))
+	}
+}
+
+// entityFilerCategoriesEN maps filer categories to the values of dei:EntityFilerCategory.
+var entityFilerCategoriesEN = map[string]string{
+	"large accelerated filer": "Large Accelerated Filer",
+	"accelerated filer":       "Accelerated Filer",
+	"non-accelerated filer":   "Non-accelerated Filer",
+	"non accelerated filer":   "Non-accelerated Filer",
+}
+
+// exchangeCodesEN maps the names of US exchanges to the values of dei:SecurityExchangeName.
+var exchangeCodesEN = map[string]string{
+	"new york stock exchange":        "NYSE",
+	"nyse":                           "NYSE",
+	"nasdaq":                         "NASDAQ",
+	"nasdaq stock market":            "NASDAQ",
+	"nasdaq stock market llc":        "NASDAQ",
+	"nasdaq global select market":    "NASDAQ",
+	"nasdaq global market":           "NASDAQ",
+	"nasdaq capital market":          "NASDAQ",
+	"nyse american":                  "NYSEAMER",
+	"nyse american llc":              "NYSEAMER",
+	"nyse arca":                      "NYSEArca",
+	"nyse arca, inc.":                "NYSEArca",
+	"cboe bzx exchange":              "CboeBZX",
+	"cboe byx exchange":              "CboeBYX",
+	"cboe edga exchange":             "CboeEDGA",
+	"cboe edgx exchange":             "CboeEDGX",
+	"chicago board options exchange": "CBOE",
+	"cboe exchange":                  "CBOE",
+	"investors exchange":             "IEX",
+}
+
+// usStateCodes maps the names of US states and territories to their USPS codes, which EDGAR also uses.
+var usStateCodes = map[string]string{
+	"alabama": "AL", "alaska": "AK", "arizona": "AZ", "arkansas": "AR", "california": "CA",
+	"colorado": "CO", "connecticut": "CT", "delaware": "DE", "district of columbia": "DC", "florida": "FL",
+	"georgia": "GA", "hawaii": "HI", "idaho": "ID", "illinois": "IL", "indiana": "IN",
+	"iowa": "IA", "kansas": "KS", "kentucky": "KY", "louisiana": "LA", "maine": "ME",
+	"maryland": "MD", "massachusetts": "MA", "michigan": "MI", "minnesota": "MN", "mississippi": "MS",
+	"missouri": "MO", "montana": "MT", "nebraska": "NE", "nevada": "NV", "new hampshire": "NH",
+	"new jersey": "NJ", "new mexico": "NM", "new york": "NY", "north carolina": "NC", "north dakota": "ND",
+	"ohio": "OH", "oklahoma": "OK", "oregon": "OR", "pennsylvania": "PA", "rhode island": "RI",
+	"south carolina": "SC", "south dakota": "SD", "tennessee": "TN", "texas": "TX", "utah": "UT",
+	"vermont": "VT", "virginia": "VA", "washington": "WA", "west virginia": "WV", "wisconsin": "WI",
+	"wyoming": "WY", "american samoa": "AS", "guam": "GU", "northern mariana islands": "MP",
+	"puerto rico": "PR", "united states virgin islands": "VI", "u.s. virgin islands": "VI",
+}
+
+// canadianProvinceCodes maps the names of Canadian provinces and territories to their postal codes.
+var canadianProvinceCodes = map[string]string{
+	"alberta": "AB", "british columbia": "BC", "manitoba": "MB", "new brunswick": "NB",
+	"newfoundland and labrador": "NL", "newfoundland": "NL", "northwest territories": "NT", "nova scotia": "NS",
+	"nunavut": "NU", "ontario": "ON", "prince edward island": "PE", "quebec": "QC", "québec": "QC",
+	"saskatchewan": "SK", "yukon": "YT",
+}
+
+// stateProvinceCodesEN maps US states and Canadian provinces to their two letter codes.
+var stateProvinceCodesEN = mergeCodes(usStateCodes, canadianProvinceCodes)
+
+// edgarStateCountryCodesEN maps US states, Canadian provinces and countries to EDGAR state and country codes.
+var edgarStateCountryCodesEN = mergeCodes(usStateCodes, edgarCountryCodes)
+
+// mergeCodes returns a new table containing the entries of all the given tables. Later tables take precedence.
+func mergeCodes(tables ...map[string]string) map[string]string {
+	merged := make(map[string]string)
+	for _, table := range tables {
+		for name, code := range table {
+			merged[name] = code
+		}
+	}
+
+	return merged
+}
@@ -0,0 +1,153 @@
+package xbrl
+
+import (
+	"encoding/xml"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDefaultTransforms(t *testing.T) {
+	registry := DefaultTransforms()
+
+	valid := []struct {
+		space, local string
+		displayed    string
+		expected     string
+	}{
+		{NamespaceTransformationRegistry1, "numcommadot", "1,234,567.89", "1234567.89"},
+		{NamespaceTransformationRegistry1, "numdotcomma", "1.234.567,89", "1234567.89"},
+		{NamespaceTransformationRegistry1, "numspacecomma", "1 234,5", "1234.5"},
+		{NamespaceTransformationRegistry1, "numdash", "—", "0"},
+		{NamespaceTransformationRegistry1, "dateslashus", "03/27/21", "2021-03-27"},
+		{NamespaceTransformationRegistry1, "datedoteu", "27.03.2021", "2021-03-27"},
+		{NamespaceTransformationRegistry1, "datelongus", "March 27, 2021", "2021-03-27"},
+		{NamespaceTransformationRegistry1, "dateshortuk", "27 Mar 2021", "2021-03-27"},
+
+		{NamespaceTransformationRegistry2, "zerodash", " - ", "0"},
+		{NamespaceTransformationRegistry2, "numunitdecimal", "5 dollars 7 cents", "5.07"},
+		{NamespaceTransformationRegistry2, "booleantrue", "Yes", "true"},
+		{NamespaceTransformationRegistry2, "datemonthdayen", "February 29", "--02-29"},
+		{NamespaceTransformationRegistry2, "dateyearmonthdaycjk", "２０２１年３月２７日", "2021-03-27"},
+		{NamespaceTransformationRegistry2, "dateerayearmonthdayjp", "平成23年3月31日", "2011-03-31"},
+		{NamespaceTransformationRegistry2, "dateerayearmonthjp", "令和元年5月", "2019-05"},
+
+		{NamespaceTransformationRegistry3, "numdotdecimal", "727,432", "727432"},
+		{NamespaceTransformationRegistry3, "numcommadecimal", "0,64", "0.64"},
+		{NamespaceTransformationRegistry3, "numdotdecimalin", "1,00,00,000.5", "10000000.5"},
+		{NamespaceTransformationRegistry3, "datedaymonthyeardk", "27. marts 2021", "2021-03-27"},
+
+		{NamespaceTransformationRegistry4, "num-dot-decimal", "89,584", "89584"},
+		{NamespaceTransformationRegistry4, "num-dot-decimal", ".5", "0.5"},
+		{NamespaceTransformationRegistry4, "num-comma-decimal", "1 234,", "1234"},
+		{NamespaceTransformationRegistry4, "fixed-zero", "nil", "0"},
+		{NamespaceTransformationRegistry4, "fixed-empty", "anything", ""},
+		{NamespaceTransformationRegistry4, "date-monthname-day-year-en", "September 26th, 2020", "2020-09-26"},
+		{NamespaceTransformationRegistry4, "date-day-monthname-year-es", "27 de marzo de 2021", "2021-03-27"},
+		{NamespaceTransformationRegistry4, "date-day-monthname-year-de", "27. März 2021", "2021-03-27"},
+		{NamespaceTransformationRegistry4, "date-day-monthname-year-fi", "27. maaliskuuta 2021", "2021-03-27"},
+		{NamespaceTransformationRegistry4, "date-monthname-year-fr", "août 2021", "2021-08"},
+		{NamespaceTransformationRegistry4, "date-year-month-day", "2021-03-27", "2021-03-27"},
+
+		{NamespaceTransformationRegistry5, "num-dot-decimal-apos", "1'234'567.89", "1234567.89"},
+		{NamespaceTransformationRegistry5, "date-day-monthroman-year", "27.III.2021", "2021-03-27"},
+		{NamespaceTransformationRegistry5, "date-monthname-day-year-en", "Mar. 27, 2021", "2021-03-27"},
+
+		{NamespaceTransformationSEC, "numwordsen", "One hundred and twenty-three", "123"},
+		{NamespaceTransformationSEC, "numwordsen", "two million five hundred thousand", "2500000"},
+		{NamespaceTransformationSEC, "numwordsen", "None", "0"},
+		{NamespaceTransformationSEC, "numwordsen", "one hundred thousand million", "100000000000"},
+		{NamespaceTransformationSEC, "numwordsen", "one billion trillion", "1000000000000000000000"},
+		{NamespaceTransformationSEC, "numwordsen", "ninety-nine thousand nine hundred ninety-nine", "99999"},
+		{NamespaceTransformationSEC, "numinf", "unlimited", "INF"},
+		{NamespaceTransformationSEC, "duryear", "1.5", "P1Y6M"},
+		{NamespaceTransformationSEC, "durmonth", "-3", "-P3M"},
+		{NamespaceTransformationSEC, "durday", "1.25", "P1DT6H"},
+		{NamespaceTransformationSEC, "durhour", "0", "P0D"},
+		{NamespaceTransformationSEC, "durwordsen", "two years and six months", "P2Y6M"},
+		{NamespaceTransformationSEC, "durwordsen", "Five years, 3 weeks", "P5Y21D"},
+		{NamespaceTransformationSEC, "datequarterend", "Q1 2021", "2021-03-31"},
+		{NamespaceTransformationSEC, "datequarterend", "third quarter of 2020", "2020-09-30"},
+		{NamespaceTransformationSEC, "boolballotbox", "☒", "true"},
+		{NamespaceTransformationSEC, "yesnoballotbox", "☐", "No"},
+		{NamespaceTransformationSEC, "stateprovnameen", "  New  York ", "NY"},
+		{NamespaceTransformationSEC, "countrynameen", "Cayman Islands", "KY"},
+		{NamespaceTransformationSEC, "countrynameen", "Côte d'Ivoire", "CI"},
+		{NamespaceTransformationSEC, "countrynameen", "Bolivia, Plurinational State of", "BO"},
+		{NamespaceTransformationSEC, "countrynameen", "Zimbabwe", "ZW"},
+		{NamespaceTransformationSEC, "exchnameen", "The Nasdaq Stock Market LLC", "NASDAQ"},
+		{NamespaceTransformationSEC, "entityfilercategoryen", "Large accelerated filer", "Large Accelerated Filer"},
+		{NamespaceTransformationSEC, "edgarprovcountryen", "Ontario, Canada", "A6"},
+		{NamespaceTransformationSEC, "edgarprovcountryen", "Saskatchewan", "A9"},
+		{NamespaceTransformationSEC, "edgarprovcountryen", "Zimbabwe", "Y5"},
+		{NamespaceTransformationSEC, "edgarprovcountryen", "Georgia", "GA"},
+		{NamespaceTransformationSEC, "edgarprovcountryen", "Republic of Georgia", "2Q"},
+	}
+
+	for _, tc := range valid {
+		tc := tc
+		t.Run(tc.local+" "+tc.displayed, func(t *testing.T) {
+			value, err := registry.Transform(xml.Name{Space: tc.space, Local: tc.local}, tc.displayed)
+			require.NoError(t, err)
+			assert.Equal(t, tc.expected, value)
+		})
+	}
+
+	invalid := []struct {
+		space, local string
+		displayed    string
+	}{
+		{NamespaceTransformationRegistry1, "numcommadot", "1.234,5"},
+		{NamespaceTransformationRegistry2, "zerodash", "0"},
+		{NamespaceTransformationRegistry3, "numdotdecimal", "12,34"},
+		{NamespaceTransformationRegistry4, "num-dot-decimal", ""},
+		{NamespaceTransformationRegistry4, "num-dot-decimal", "1,234 567"},
+		{NamespaceTransformationRegistry4, "num-comma-decimal", "1.234 567,8"},
+		{NamespaceTransformationRegistry4, "num-unit-decimal", "1,234.567,8"},
+		{NamespaceTransformationRegistry4, "date-month-day-year", "foo 03/27/2021"},
+		{NamespaceTransformationRegistry4, "date-year-month-day", "2021-03-27 $"},
+		{NamespaceTransformationRegistry4, "date-month-day-year", "02/30/2021"},
+		{NamespaceTransformationRegistry4, "date-month-day-year", "March 27, 2021"},
+		{NamespaceTransformationRegistry4, "date-monthname-day-year-en", "Marchember 27, 2021"},
+		{NamespaceTransformationRegistry4, "date-monthname-day-year-en", "March 27"},
+		{NamespaceTransformationSEC, "numwordsen", "eleventy"},
+		{NamespaceTransformationSEC, "numwordsen", "one two"},
+		{NamespaceTransformationSEC, "numwordsen", "twelve one"},
+		{NamespaceTransformationSEC, "numwordsen", "twenty thirty"},
+		{NamespaceTransformationSEC, "numwordsen", "one trillion trillion"},
+		{NamespaceTransformationSEC, "numwordsen", "two thousand three million"},
+		{NamespaceTransformationSEC, "durwordsen", "ten trillion years"},
+		{NamespaceTransformationSEC, "datequarterend", "Q5 2021"},
+		{NamespaceTransformationSEC, "boolballotbox", "X"},
+		{NamespaceTransformationSEC, "countrynameen", "Atlantis"},
+	}
+
+	for _, tc := range invalid {
+		tc := tc
+		t.Run("invalid "+tc.local+" "+tc.displayed, func(t *testing.T) {
+			_, err := registry.Transform(xml.Name{Space: tc.space, Local: tc.local}, tc.displayed)
+
+			var transformErr *TransformError
+			require.ErrorAs(t, err, &transformErr)
+			assert.Equal(t, tc.displayed, transformErr.Value)
+			assert.NotErrorIs(t, err, ErrUnknownTransform)
+		})
+	}
+
+	t.Run("every country", func(t *testing.T) {
+		codes := make(map[string]bool)
+		for _, code := range countryCodesEN {
+			codes[code] = true
+		}
+
+		// All the officially assigned codes of ISO 3166-1.
+		assert.Len(t, codes, 249)
+		assert.Len(t, edgarCountryCodesByISO, 244)
+	})
+
+	t.Run("unknown format", func(t *testing.T) {
+		_, err := registry.Transform(xml.Name{Space: NamespaceTransformationRegistry4, Local: "num-roman"}, "XII")
+		assert.ErrorIs(t, err, ErrUnknownTransform)
+	})
+}
@@ -0,0 +1,164 @@
+package xbrl
+
+import (
+	"encoding/xml"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Tuple is a fact that binds together other facts (and tuples) that can't be understood on their own.
+// For example, a director's name, age and compensation only make sense together, so they're reported as the children of one tuple per director.
+//
+// Unlike items, tuples don't reference a context or unit, and have no value of their own.
+// https://www.xbrl.org/Specification/XBRL-2.1/REC-2003-12-31/XBRL-2.1-REC-2003-12-31+corrected-errata-2013-02-20.html#_4.9
+type Tuple struct {
+	XMLName xml.Name
+
+	// Name is the concept of the tuple with its namespace resolved and its original prefix. See Fact.Name.
+	Name QName
+
+	// ID uniquely identifies a tuple within an XBRL document. It's optional.
+	ID string
+
+	// Nil is non-nil if the tuple has an xsi:nil attribute.
+	Nil *bool
+
+	// Parent is the tuple that contains this tuple, or nil if this is a top-level tuple.
+	Parent *Tuple
+
+	// Children are the facts and tuples in this tuple, in document order.
+	Children []TupleChild
+
+	// Position is where the tuple is in the document it was decoded from.
+	Position Position
+}
+
+// TupleChild is a fact or tuple inside of a Tuple. Exactly one of Fact and Tuple is non-nil.
+type TupleChild struct {
+	// Fact points to the Fact in RawXBRL.Facts (or XBRL.Facts) for item children.
+	Fact *Fact
+
+	// Tuple is non-nil for tuples nested in another tuple.
+	Tuple *Tuple
+
+	// factIndex is the index of Fact in RawXBRL.Facts, which is turned into the Fact pointer once all facts are decoded.
+	factIndex int
+}
+
+// Facts returns the facts that are direct children of this tuple, in document order.
+func (t *Tuple) Facts() []*Fact {
+	var facts []*Fact
+	for _, child := range t.Children {
+		if child.Fact != nil {
+			facts = append(facts, child.Fact)
+		}
+	}
+
+	return facts
+}
+
+// Tuples returns the tuples that are direct children of this tuple, in document order.
+func (t *Tuple) Tuples() []*Tuple {
+	var tuples []*Tuple
+	for _, child := range t.Children {
+		if child.Tuple != nil {
+			tuples = append(tuples, child.Tuple)
+		}
+	}
+
+	return tuples
+}
+
+// decodeItem decodes a fact or tuple element in an XBRL document.
+// Facts are appended to r.Facts, and tuples are returned along with their children.
+// If the element is a fact, the returned tuple is nil and factIndex is the index of the fact in r.Facts.
+//
+// Items always have a contextRef attribute, so anything that doesn't and has child elements is treated as a tuple,
+// as is an empty element with xsi:nil="true", which is a nil tuple. Anything else without a contextRef,
+// like <us-gaap:Revenues/> or <us-gaap:Revenues>100</us-gaap:Revenues>, is kept as a (malformed) fact, which Fact.IsValid() rejects.
+// The namespaces declared by the element are added to scope before its name is resolved, and pos is the position of its start tag.
+func (r *RawXBRL) decodeItem(d *xml.Decoder, start xml.StartElement, pos Position, scope namespaceScope, strs *stringInterner) (tuple *Tuple, factIndex int, err error) {
+	scope = scope.with(start)
+	for _, attr := range start.Attr {
+		if attr.Name.Local == "contextRef" {
+			fact, err := decodeFact(d, start, strs)
+			if err != nil {
+				return nil, 0, fmt.Errorf("fact at %s: %w", pos, err)
+			}
+
+			fact.Name = scope.qname(fact.XMLName)
+			fact.Lang = scope.lang()
+			fact.Position = pos
+			r.Facts = append(r.Facts, fact)
+			return nil, len(r.Facts) - 1, nil
+		}
+	}
+
+	tuple = &Tuple{XMLName: start.Name, Name: scope.qname(start.Name), Position: pos}
+	for _, attr := range start.Attr {
+		switch attr.Name.Local {
+		case "id":
+			tuple.ID = attr.Value
+		case "nil":
+			isNil, err := strconv.ParseBool(strings.TrimSpace(attr.Value))
+			if err != nil {
+				return nil, 0, fmt.Errorf("tuple at %s: %w", pos, err)
+			}
+
+			tuple.Nil = sharedBool(isNil)
+		}
+	}
+
+	var text strings.Builder
+	for {
+		childPos := decoderPosition(d)
+		token, err := d.Token()
+		if err != nil {
+			return nil, 0, err
+		}
+
+		switch t := token.(type) {
+		case xml.StartElement:
+			child, childIndex, err := r.decodeItem(d, t, childPos, scope, strs)
+			if err != nil {
+				return nil, 0, err
+			}
+
+			if child != nil {
+				child.Parent = tuple
+				tuple.Children = append(tuple.Children, TupleChild{Tuple: child})
+			} else {
+				tuple.Children = append(tuple.Children, TupleChild{factIndex: childIndex})
+			}
+		case xml.CharData:
+			text.Write(t)
+		case xml.EndElement:
+			if len(tuple.Children) > 0 || (tuple.Nil != nil && *tuple.Nil && strings.TrimSpace(text.String()) == "") {
+				return tuple, 0, nil
+			}
+
+			// No child elements means this is a fact that's missing its contextRef.
+			value := text.String()
+			r.Facts = append(r.Facts, Fact{XMLName: start.Name, Name: tuple.Name, ID: tuple.ID, Nil: tuple.Nil, ValueStr: strs.value(value), Position: pos})
+			return nil, len(r.Facts) - 1, nil
+		}
+	}
+}
+
+// linkTupleFacts points the children of tuples to their facts in r.Facts, and the facts back to their tuples.
+// This can only happen once all facts are decoded, since appending to r.Facts can move its elements.
+func (r *RawXBRL) linkTupleFacts(tuples []*Tuple) {
+	for _, tuple := range tuples {
+		for index := range tuple.Children {
+			child := &tuple.Children[index]
+			if child.Tuple != nil {
+				r.linkTupleFacts([]*Tuple{child.Tuple})
+				continue
+			}
+
+			child.Fact = &r.Facts[child.factIndex]
+			child.Fact.Tuple = tuple
+		}
+	}
+}
@@ -0,0 +1,120 @@
+package xbrl
+
+import (
+	"encoding/xml"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestUnmarshalTuples(t *testing.T) {
+	t.Run("nested tuples", func(t *testing.T) {
+		// language=xml
+		xbrlXML := `<xbrl xmlns="http://www.xbrl.org/2003/instance" xmlns:my="http://example.com/taxonomy">
+    <context id="c1">
+        <entity>
+            <identifier scheme="http://www.sec.gov/CIK">0000320193</identifier>
+        </entity>
+        <period>
+            <instant>2021-03-27</instant>
+        </period>
+    </context>
+    <unit id="usd">
+        <measure>iso4217:USD</measure>
+    </unit>
+
+    <my:Revenues contextRef="c1" unitRef="usd" decimals="0">100</my:Revenues>
+    <my:Directors id="directors">
+        <my:Director>
+            <my:Name contextRef="c1">Jane Doe</my:Name>
+            <my:Compensation contextRef="c1" unitRef="usd" decimals="0">1000</my:Compensation>
+        </my:Director>
+        <my:Director>
+            <my:Name contextRef="c1">John Doe</my:Name>
+        </my:Director>
+        <my:Count contextRef="c1" unitRef="usd" decimals="0">2</my:Count>
+    </my:Directors>
+    <my:EmptyTuple xsi:nil="true" xmlns:xsi="http://www.w3.org/2001/XMLSchema-instance"/>
+</xbrl>`
+
+		var content XBRL
+		require.NoError(t, xml.Unmarshal([]byte(xbrlXML), &content))
+		require.NoError(t, content.Validate())
+
+		require.Len(t, content.Facts, 5)
+		assert.Equal(t, "Revenues", content.Facts[0].XMLName.Local)
+		assert.Nil(t, content.Facts[0].Tuple)
+
+		require.Len(t, content.Tuples, 2)
+		directors := content.Tuples[0]
+		assert.Equal(t, xml.Name{Space: "http://example.com/taxonomy", Local: "Directors"}, directors.XMLName)
+		assert.Equal(t, "directors", directors.ID)
+		assert.Nil(t, directors.Parent)
+		require.Len(t, directors.Children, 3)
+
+		directorTuples := directors.Tuples()
+		require.Len(t, directorTuples, 2)
+		assert.Same(t, directors, directorTuples[0].Parent)
+
+		firstDirector := directorTuples[0].Facts()
+		require.Len(t, firstDirector, 2)
+		assert.Equal(t, "Jane Doe", firstDirector[0].Value())
+		assert.Equal(t, "1000", firstDirector[1].Value())
+		assert.Same(t, &content.Facts[1], firstDirector[0])
+		assert.Same(t, directorTuples[0], content.Facts[1].Tuple)
+
+		secondDirector := directorTuples[1].Facts()
+		require.Len(t, secondDirector, 1)
+		assert.Equal(t, "John Doe", secondDirector[0].Value())
+
+		count := directors.Facts()
+		require.Len(t, count, 1)
+		assert.Equal(t, "Count", count[0].XMLName.Local)
+		assert.Same(t, directors, count[0].Tuple)
+
+		emptyTuple := content.Tuples[1]
+		assert.Equal(t, "EmptyTuple", emptyTuple.XMLName.Local)
+		require.NotNil(t, emptyTuple.Nil)
+		assert.True(t, *emptyTuple.Nil)
+		assert.Empty(t, emptyTuple.Children)
+	})
+
+	t.Run("text without a context is still a malformed fact", func(t *testing.T) {
+		// language=xml
+		xbrlXML := `<xbrl>
+    <my:Name>Jane Doe</my:Name>
+</xbrl>`
+
+		var content XBRL
+		require.NoError(t, xml.Unmarshal([]byte(xbrlXML), &content))
+		assert.Empty(t, content.Tuples)
+		require.Len(t, content.Facts, 1)
+		assert.Equal(t, "Jane Doe", content.Facts[0].Value())
+		assert.Error(t, content.Validate())
+	})
+
+	t.Run("empty element without a context is a malformed fact", func(t *testing.T) {
+		// language=xml
+		xbrlXML := `<xbrl xmlns:my="http://example.com/taxonomy">
+    <my:Revenues/>
+</xbrl>`
+
+		var content XBRL
+		require.NoError(t, xml.Unmarshal([]byte(xbrlXML), &content))
+		assert.Empty(t, content.Tuples)
+		require.Len(t, content.Facts, 1)
+		assert.Equal(t, "Revenues", content.Facts[0].XMLName.Local)
+		assert.Error(t, content.Validate())
+	})
+
+	t.Run("invalid nil", func(t *testing.T) {
+		// language=xml
+		xbrlXML := `<xbrl xmlns:my="http://example.com/taxonomy" xmlns:xsi="http://www.w3.org/2001/XMLSchema-instance">
+    <my:Directors xsi:nil="maybe"><my:Name contextRef="c1">Jane Doe</my:Name></my:Directors>
+</xbrl>`
+
+		var content XBRL
+		assert.Error(t, xml.Unmarshal([]byte(xbrlXML), &content))
+	})
+}
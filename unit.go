@@ -1,6 +1,19 @@
 package xbrl
 
-import "strings"
+import (
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// NamespaceISO4217 is the namespace of currency measures, ie iso4217:USD.
+const NamespaceISO4217 = "http://www.xbrl.org/2003/iso4217"
+
+// ErrInvalidUnit is returned (wrapped) by Unit.Validate() when a unit breaks the rules of section 4.8 of the spec.
+var ErrInvalidUnit = errors.New("invalid unit")
 
 // Unit specifies the unit in which a numeric fact has been measured.
 // A Unit can be either a simple measure, product of measures, or a ratio of products of measures with a numerator and a denominator.
@@ -16,6 +29,9 @@ type Unit struct {
 	ID       string   `xml:"id,attr"`
 	Measures Measures `xml:"measure"`
 	Divide   *Divide  `xml:"divide"`
+
+	// Position is where the unit is in the document it was decoded from.
+	Position Position `xml:"-"`
 }
 
 // Divide represents a ratios of Units that has a numerator and a denominator.
@@ -42,13 +58,50 @@ type Divide struct {
 // plain text:     <measure>shares</measure>
 //
 // Note that if the value is XML namespaced, the namespace should be declared in the XML, but this parser does not validate that.
+// If it isn't declared, Name.Space is left empty.
 // https://www.xbrl.org/Specification/XBRL-2.1/REC-2003-12-31/XBRL-2.1-REC-2003-12-31+corrected-errata-2013-02-20.html#_4.8.2
 type Measure struct {
 	Value string `xml:",chardata"`
+
+	// Name is the measure parsed as a QName. Its namespace is resolved when the unit is decoded as part of an XBRL document,
+	// so iso4217:USD can be recognized whatever prefix the filer bound to http://www.xbrl.org/2003/iso4217.
+	// Plain text measures like "shares" are in the default namespace, which is usually the XBRL instance namespace.
+	Name QName `xml:"-"`
 }
 
 type Measures []Measure
 
+// UnmarshalXML implements xml.Unmarshaler for Measure. It decodes Value and parses it into Name.
+func (m *Measure) UnmarshalXML(d *xml.Decoder, start xml.StartElement) error {
+	var value string
+	if err := d.DecodeElement(&value, &start); err != nil {
+		return err
+	}
+
+	*m = Measure{Value: value, Name: ParseQName(value)}
+	return nil
+}
+
+// resolveNames resolves the QNames of all the measures in the Unit against the namespaces in scope.
+func (u *Unit) resolveNames(scope namespaceScope) {
+	for _, measures := range []Measures{u.Measures, u.dividedMeasures(false), u.dividedMeasures(true)} {
+		for index := range measures {
+			measures[index].Name = scope.resolve(measures[index].Name)
+		}
+	}
+}
+
+// dividedMeasures returns the numerator (or denominator) measures of the Divide, or nil if the unit isn't a ratio.
+func (u Unit) dividedMeasures(denominator bool) Measures {
+	if u.Divide == nil {
+		return nil
+	} else if denominator {
+		return u.Divide.Denominator
+	}
+
+	return u.Divide.Numerator
+}
+
 // String returns a human readable representation of the Unit.
 func (u Unit) String() string {
 	// If the Divide element is not nil, there can be no top-level Meaures.
@@ -64,11 +117,11 @@ func (u Unit) String() string {
 // Ex: `<measure>iso4127:USD</measure>` -> "USD"
 //     `<measure>shares</measure>`      -> "shares"
 func (m Measure) String() string {
-	if index := strings.IndexRune(m.Value, ':'); index != -1 && index < len(m.Value) {
-		return m.Value[index+1 : len(m.Value)]
+	if m.Name.Local != "" {
+		return m.Name.Local
 	}
 
-	return m.Value
+	return ParseQName(m.Value).Local
 }
 
 // String returns a human readable representation of the product of all the `Measure`s in this slice.
@@ -85,3 +138,182 @@ func (m Measures) String() string {
 
 	return builder.String()
 }
+
+// resolvedName returns the QName of the measure, with its namespace guessed from its prefix if it wasn't resolved.
+// That's the case for units that weren't decoded as part of a document, or documents that don't declare the namespaces they use:
+// the iso4217 prefix is taken to mean NamespaceISO4217, and the xbrli prefix NamespaceXBRLI.
+// Unprefixed measures are only in NamespaceXBRLI if it's the default namespace where they're declared, so they're left unresolved otherwise.
+func (m Measure) resolvedName() QName {
+	name := m.Name
+	if name.Local == "" {
+		name = ParseQName(m.Value)
+	}
+
+	if name.Space == "" {
+		switch name.Prefix {
+		case "iso4217":
+			name.Space = NamespaceISO4217
+		case "xbrli":
+			name.Space = NamespaceXBRLI
+		}
+	}
+
+	return name
+}
+
+// IsCurrency returns true if the measure is in the ISO 4217 namespace, ie iso4217:USD.
+func (m Measure) IsCurrency() bool {
+	return m.resolvedName().Space == NamespaceISO4217
+}
+
+// isXBRLI returns true if the measure is xbrli:<local>, ie xbrli:shares or xbrli:pure.
+// The measure is resolved the same way as everywhere else (see resolvedName()), so an unprefixed measure
+// is only in NamespaceXBRLI if that's the default namespace where it's declared.
+func (m Measure) isXBRLI(local string) bool {
+	name := m.resolvedName()
+	return name.Space == NamespaceXBRLI && name.Local == local
+}
+
+// currencyCodePattern matches ISO 4217 alphabetic currency codes.
+// It only checks their form rather than a list of codes, since codes are added and withdrawn over time and old filings use withdrawn ones.
+var currencyCodePattern = regexp.MustCompile(`^[A-Z]{3}$`)
+
+// Validate checks the unit against section 4.8 of the spec:
+//   - It must be either a product of one or more measures or a divide, but not both.
+//   - A divide must have at least one measure in both its numerator and its denominator, and they can't have a measure in common.
+//   - Measures in the ISO 4217 namespace must be currency codes, ie iso4217:USD but not iso4217:usd or iso4217:Dollar.
+//   - The only measures in the XBRL instance namespace are xbrli:shares and xbrli:pure.
+//
+// https://www.xbrl.org/Specification/XBRL-2.1/REC-2003-12-31/XBRL-2.1-REC-2003-12-31+corrected-errata-2013-02-20.html#_4.8
+func (u Unit) Validate() error {
+	switch {
+	case u.Divide != nil && len(u.Measures) > 0:
+		return fmt.Errorf("%w: both measures and a divide", ErrInvalidUnit)
+	case u.Divide == nil && len(u.Measures) == 0:
+		return fmt.Errorf("%w: no measures", ErrInvalidUnit)
+	case u.Divide != nil && len(u.Divide.Numerator) == 0:
+		return fmt.Errorf("%w: divide without a numerator measure", ErrInvalidUnit)
+	case u.Divide != nil && len(u.Divide.Denominator) == 0:
+		return fmt.Errorf("%w: divide without a denominator measure", ErrInvalidUnit)
+	}
+
+	for _, measures := range []Measures{u.Measures, u.dividedMeasures(false), u.dividedMeasures(true)} {
+		for _, measure := range measures {
+			if err := measure.validate(); err != nil {
+				return err
+			}
+		}
+	}
+
+	for _, numerator := range u.dividedMeasures(false) {
+		for _, denominator := range u.dividedMeasures(true) {
+			if numerator.resolvedName().Equal(denominator.resolvedName()) {
+				return fmt.Errorf("%w: measure %s is in both the numerator and the denominator", ErrInvalidUnit, strings.TrimSpace(numerator.Value))
+			}
+		}
+	}
+
+	return nil
+}
+
+// validate checks a single measure. See Unit.Validate().
+func (m Measure) validate() error {
+	name := m.resolvedName()
+	switch {
+	case name.Local == "":
+		return fmt.Errorf("%w: empty measure", ErrInvalidUnit)
+	case name.Space == NamespaceISO4217 && !currencyCodePattern.MatchString(name.Local):
+		return fmt.Errorf("%w: %s isn't an ISO 4217 currency code", ErrInvalidUnit, strings.TrimSpace(m.Value))
+	case name.Space == NamespaceXBRLI && name.Local != "shares" && name.Local != "pure":
+		return fmt.Errorf("%w: %s isn't an XBRL instance measure", ErrInvalidUnit, strings.TrimSpace(m.Value))
+	}
+
+	return nil
+}
+
+// Normalize returns the simplest form of the unit, which is the same for units that measure the same thing:
+//   - Top-level measures next to a divide are moved into its numerator, since they multiply it.
+//   - Measures that are in both the numerator and the denominator cancel out, and a divide without a denominator (or whose denominator
+//     is xbrli:pure) becomes a product.
+//   - xbrli:pure is left out of products of other measures, since it has no dimension. A unit where everything cancels out is xbrli:pure.
+//   - Measures are sorted by namespace and local name, so their order in the document doesn't matter.
+//
+// The ID and Position of the unit are kept. A numerator that's left empty is xbrli:pure, ie pure / shares.
+// Normalize doesn't validate the unit (see Validate()), so a unit without any measure is normalized to xbrli:pure too.
+func (u Unit) Normalize() Unit {
+	numerator := append(append(Measures(nil), u.Measures...), u.dividedMeasures(false)...)
+	denominator := append(Measures(nil), u.dividedMeasures(true)...)
+
+	// Cancel out the measures that are on both sides, one for one.
+	for i := 0; i < len(numerator); i++ {
+		for j := range denominator {
+			if numerator[i].resolvedName().Equal(denominator[j].resolvedName()) {
+				numerator = append(numerator[:i], numerator[i+1:]...)
+				denominator = append(denominator[:j], denominator[j+1:]...)
+				i--
+				break
+			}
+		}
+	}
+
+	// Dividing by xbrli:pure (or nothing) doesn't change the numerator.
+	numerator, denominator = numerator.normalize(), denominator.normalize()
+	if len(denominator) == 1 && denominator[0].isXBRLI("pure") {
+		return Unit{ID: u.ID, Measures: numerator, Position: u.Position}
+	}
+
+	return Unit{ID: u.ID, Divide: &Divide{Numerator: numerator, Denominator: denominator}, Position: u.Position}
+}
+
+// pureMeasure is the xbrli:pure measure that Normalize() uses for units without any other measure.
+var pureMeasure = Measure{Value: "xbrli:pure", Name: QName{Space: NamespaceXBRLI, Local: "pure", Prefix: "xbrli"}}
+
+// normalize sorts the measures and removes xbrli:pure from them, or returns just xbrli:pure if there's nothing else.
+// The returned slice doesn't share its array with m.
+func (m Measures) normalize() Measures {
+	normalized := make(Measures, 0, len(m))
+	for _, measure := range m {
+		if !measure.isXBRLI("pure") {
+			normalized = append(normalized, measure)
+		}
+	}
+
+	if len(normalized) == 0 {
+		return Measures{pureMeasure}
+	}
+
+	sort.SliceStable(normalized, func(i, j int) bool {
+		return qnameKey(normalized[i].resolvedName()) < qnameKey(normalized[j].resolvedName())
+	})
+
+	return normalized
+}
+
+// IsMonetary returns true if the unit is a single currency, ie iso4217:USD, as monetary facts must be.
+func (u Unit) IsMonetary() bool {
+	normalized := u.Normalize()
+	return len(normalized.Measures) == 1 && normalized.Measures[0].IsCurrency()
+}
+
+// IsShares returns true if the unit is xbrli:shares, as facts about a number of shares must be.
+func (u Unit) IsShares() bool {
+	normalized := u.Normalize()
+	return len(normalized.Measures) == 1 && normalized.Measures[0].isXBRLI("shares")
+}
+
+// IsPure returns true if the unit is xbrli:pure, or a ratio of the same measures like USD / USD, which are used for percentages and ratios.
+func (u Unit) IsPure() bool {
+	normalized := u.Normalize()
+	return len(normalized.Measures) == 1 && normalized.Measures[0].isXBRLI("pure")
+}
+
+// IsPerShare returns true if the unit is something per share, ie iso4217:USD / xbrli:shares for earnings per share.
+func (u Unit) IsPerShare() bool {
+	normalized := u.Normalize()
+	if normalized.Divide == nil {
+		return false
+	}
+
+	denominator := normalized.Divide.Denominator
+	return len(denominator) == 1 && denominator[0].isXBRLI("shares")
+}
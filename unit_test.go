@@ -2,6 +2,7 @@ package xbrl
 
 import (
 	"encoding/xml"
+	"os"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -106,3 +107,253 @@ func TestUnmarshalUnit(t *testing.T) {
 		assert.Equal(t, "USD / feet * feet", unit.String())
 	})
 }
+
+// language=xml
+const unitsXBRL = `<xbrl xmlns="http://www.xbrl.org/2003/instance" xmlns:currency="http://www.xbrl.org/2003/iso4217"
+    xmlns:xbrli="http://www.xbrl.org/2003/instance" xmlns:my="http://example.com/my">
+    <unit id="usd"><measure>currency:USD</measure></unit>
+    <unit id="shares"><measure>shares</measure></unit>
+    <unit id="pure"><measure>xbrli:pure</measure></unit>
+    <unit id="usd-per-share">
+        <divide>
+            <unitNumerator><measure>currency:USD</measure></unitNumerator>
+            <unitDenominator><measure>xbrli:shares</measure></unitDenominator>
+        </divide>
+    </unit>
+    <unit id="usd-per-usd">
+        <divide>
+            <unitNumerator><measure>currency:USD</measure></unitNumerator>
+            <unitDenominator><measure>currency:USD</measure></unitDenominator>
+        </divide>
+    </unit>
+    <unit id="feet-usd-per-feet">
+        <measure>my:feet</measure>
+        <divide>
+            <unitNumerator><measure>currency:USD</measure><measure>my:feet</measure></unitNumerator>
+            <unitDenominator><measure>my:feet</measure><measure>shares</measure></unitDenominator>
+        </divide>
+    </unit>
+    <unit id="empty-numerator">
+        <divide>
+            <unitNumerator/>
+            <unitDenominator><measure>shares</measure></unitDenominator>
+        </divide>
+    </unit>
+    <unit id="empty"/>
+    <unit id="lowercase-currency"><measure>currency:usd</measure></unit>
+    <unit id="dollar"><measure>currency:Dollar</measure></unit>
+    <unit id="xbrli-feet"><measure>feet</measure></unit>
+    <unit id="usd-pure"><measure>pure</measure><measure>currency:USD</measure></unit>
+</xbrl>`
+
+func TestUnitValidate(t *testing.T) {
+	var content XBRL
+	require.NoError(t, xml.Unmarshal([]byte(unitsXBRL), &content))
+
+	testCases := []struct {
+		id  string
+		err string
+	}{
+		{"usd", ""},
+		{"shares", ""},
+		{"pure", ""},
+		{"usd-per-share", ""},
+		{"usd-pure", ""},
+		{"usd-per-usd", "invalid unit: measure currency:USD is in both the numerator and the denominator"},
+		{"feet-usd-per-feet", "invalid unit: both measures and a divide"},
+		{"empty-numerator", "invalid unit: divide without a numerator measure"},
+		{"empty", "invalid unit: no measures"},
+		{"lowercase-currency", "invalid unit: currency:usd isn't an ISO 4217 currency code"},
+		{"dollar", "invalid unit: currency:Dollar isn't an ISO 4217 currency code"},
+		{"xbrli-feet", "invalid unit: feet isn't an XBRL instance measure"},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.id, func(t *testing.T) {
+			unit, exists := content.UnitsByID[testCase.id]
+			require.True(t, exists)
+
+			err := unit.Validate()
+			if testCase.err == "" {
+				assert.NoError(t, err)
+				return
+			}
+
+			assert.ErrorIs(t, err, ErrInvalidUnit)
+			assert.EqualError(t, err, testCase.err)
+		})
+	}
+
+	t.Run("divide without a denominator", func(t *testing.T) {
+		unit := Unit{Divide: &Divide{Numerator: Measures{{Value: "iso4217:USD"}}}}
+		assert.EqualError(t, unit.Validate(), "invalid unit: divide without a denominator measure")
+	})
+
+	t.Run("unresolved namespaces", func(t *testing.T) {
+		// The iso4217 prefix isn't declared, so the currency is recognized by its prefix.
+		// language=xml
+		unitXML := `<unit><measure>iso4217:usd</measure></unit>`
+
+		var unit Unit
+		require.NoError(t, xml.Unmarshal([]byte(unitXML), &unit))
+		assert.EqualError(t, unit.Validate(), "invalid unit: iso4217:usd isn't an ISO 4217 currency code")
+	})
+
+	t.Run("unprefixed measures outside of the xbrli namespace", func(t *testing.T) {
+		// Without a default namespace, or with another one, unprefixed measures aren't XBRL instance measures.
+		// language=xml
+		const doc = `<xbrl>
+    <unit id="usd"><measure>USD</measure></unit>
+    <unit id="feet" xmlns="http://example.com/my"><measure>feet</measure></unit>
+</xbrl>`
+
+		var content XBRL
+		require.NoError(t, xml.Unmarshal([]byte(doc), &content))
+		require.Len(t, content.UnitsByID, 2)
+		for id, unit := range content.UnitsByID {
+			assert.NoError(t, unit.Validate(), id)
+		}
+	})
+}
+
+func TestUnitNormalize(t *testing.T) {
+	var content XBRL
+	require.NoError(t, xml.Unmarshal([]byte(unitsXBRL), &content))
+
+	testCases := []struct {
+		id       string
+		expected string
+	}{
+		{"usd", "USD"},
+		{"usd-per-share", "USD / shares"},
+		{"usd-per-usd", "pure"},
+		{"feet-usd-per-feet", "feet * USD / shares"},
+		{"empty-numerator", "pure / shares"},
+		{"empty", "pure"},
+		{"usd-pure", "USD"},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.id, func(t *testing.T) {
+			unit := content.UnitsByID[testCase.id]
+			normalized := unit.Normalize()
+			assert.Equal(t, testCase.expected, normalized.String())
+			assert.Equal(t, unit.ID, normalized.ID)
+			assert.Equal(t, unit.Position, normalized.Position)
+			assert.NoError(t, normalized.Validate())
+		})
+	}
+
+	t.Run("divided by pure", func(t *testing.T) {
+		unit := Unit{Divide: &Divide{Numerator: Measures{{Value: "iso4217:USD"}}, Denominator: Measures{{Value: "xbrli:pure"}}}}
+		assert.Equal(t, "USD", unit.Normalize().String())
+		assert.True(t, unit.IsMonetary())
+
+		// Without a prefix or a namespace, pure isn't xbrli:pure.
+		unit.Divide.Denominator[0].Value = "pure"
+		assert.Equal(t, "USD / pure", unit.Normalize().String())
+		assert.False(t, unit.IsMonetary())
+	})
+
+	t.Run("sorts measures", func(t *testing.T) {
+		unit := Unit{Measures: Measures{
+			{Value: "b:x", Name: QName{Space: "http://example.com/b", Local: "x", Prefix: "b"}},
+			{Value: "a:y", Name: QName{Space: "http://example.com/a", Local: "y", Prefix: "a"}},
+		}}
+
+		normalized := unit.Normalize()
+		assert.Equal(t, "y * x", normalized.String())
+		assert.Equal(t, "x * y", unit.String(), "the unit itself is left untouched")
+	})
+}
+
+func TestUnitClassification(t *testing.T) {
+	var content XBRL
+	require.NoError(t, xml.Unmarshal([]byte(unitsXBRL), &content))
+
+	testCases := []struct {
+		id                               string
+		monetary, shares, pure, perShare bool
+	}{
+		{"usd", true, false, false, false},
+		{"shares", false, true, false, false},
+		{"pure", false, false, true, false},
+		{"usd-per-share", false, false, false, true},
+		{"usd-per-usd", false, false, true, false},
+		{"usd-pure", true, false, false, false},
+		{"feet-usd-per-feet", false, false, false, true},
+		{"lowercase-currency", true, false, false, false},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.id, func(t *testing.T) {
+			unit := content.UnitsByID[testCase.id]
+			assert.Equal(t, testCase.monetary, unit.IsMonetary(), "IsMonetary")
+			assert.Equal(t, testCase.shares, unit.IsShares(), "IsShares")
+			assert.Equal(t, testCase.pure, unit.IsPure(), "IsPure")
+			assert.Equal(t, testCase.perShare, unit.IsPerShare(), "IsPerShare")
+		})
+	}
+
+	t.Run("namespaces are resolved", func(t *testing.T) {
+		// language=xml
+		const doc = `<xbrl xmlns:iso4217="http://example.com/not-currencies" xmlns:money="http://www.xbrl.org/2003/iso4217">
+    <unit id="fake"><measure>iso4217:USD</measure></unit>
+    <unit id="real"><measure>money:USD</measure></unit>
+</xbrl>`
+
+		var content XBRL
+		require.NoError(t, xml.Unmarshal([]byte(doc), &content))
+		assert.False(t, content.UnitsByID["fake"].IsMonetary())
+		assert.True(t, content.UnitsByID["real"].IsMonetary())
+
+		// Both of them are USD to Measure.String().
+		assert.Equal(t, content.UnitsByID["fake"].String(), content.UnitsByID["real"].String())
+	})
+
+	t.Run("no default namespace", func(t *testing.T) {
+		// language=xml
+		const doc = `<xbrl xmlns:xbrli="http://www.xbrl.org/2003/instance">
+    <unit id="shares"><measure>shares</measure></unit>
+    <unit id="pure"><measure>pure</measure></unit>
+    <unit id="xbrli-pure"><measure>xbrli:pure</measure></unit>
+    <unit id="per-pure"><divide><unitNumerator><measure>xbrli:shares</measure></unitNumerator><unitDenominator><measure>pure</measure></unitDenominator></divide></unit>
+</xbrl>`
+
+		var content XBRL
+		require.NoError(t, xml.Unmarshal([]byte(doc), &content))
+
+		// Unprefixed measures aren't in any namespace without a default one, so they aren't xbrli:shares or xbrli:pure.
+		assert.False(t, content.UnitsByID["shares"].IsShares())
+		assert.False(t, content.UnitsByID["pure"].IsPure())
+		assert.True(t, content.UnitsByID["xbrli-pure"].IsPure())
+
+		// That's consistent with comparing the units.
+		assert.False(t, content.UnitsByID["pure"].Equal(content.UnitsByID["xbrli-pure"]))
+		assert.NotEqual(t, content.UnitsByID["pure"].Key(), content.UnitsByID["xbrli-pure"].Key())
+
+		// And with normalizing them, which only cancels out xbrli:pure.
+		assert.False(t, content.UnitsByID["per-pure"].IsShares())
+		assert.NotNil(t, content.UnitsByID["per-pure"].Normalize().Divide)
+	})
+
+	t.Run("real-world xbrl", func(t *testing.T) {
+		f, err := os.Open("test_data/aapl-20210327_htm.xml")
+		require.NoError(t, err)
+		defer f.Close()
+
+		var content XBRL
+		require.NoError(t, xml.NewDecoder(f).Decode(&content))
+
+		assert.True(t, content.UnitsByID["usd"].IsMonetary())
+		assert.True(t, content.UnitsByID["eur"].IsMonetary())
+		assert.True(t, content.UnitsByID["shares"].IsShares())
+		assert.True(t, content.UnitsByID["number"].IsPure())
+		assert.True(t, content.UnitsByID["usdPerShare"].IsPerShare())
+		assert.False(t, content.UnitsByID["customer"].IsMonetary())
+
+		for id, unit := range content.UnitsByID {
+			assert.NoError(t, unit.Validate(), id)
+		}
+	})
+}
@@ -0,0 +1,349 @@
+package xbrl
+
+import (
+	"encoding/xml"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+type Severity string
+
+// The severities of a ValidationIssue.
+const (
+	// SeverityError is for problems that make the document invalid. XBRL.Validate() only fails if there's at least one of them.
+	SeverityError Severity = "error"
+
+	// SeverityWarning is for things that are allowed by the spec, but are most likely a mistake in the document.
+	SeverityWarning Severity = "warning"
+)
+
+type ValidationCode string
+
+// All the ValidationCode values. They're stable, so they can be used to filter or count issues across documents.
+const (
+	// ValidationCodeInvalidFact is an error for a fact that's missing some of the fields its type needs. See Fact.IsValid().
+	ValidationCodeInvalidFact ValidationCode = "invalid_fact"
+
+	// ValidationCodeMissingContext is an error for a fact whose contextRef doesn't match any context in the document.
+	ValidationCodeMissingContext ValidationCode = "missing_context"
+
+	// ValidationCodeMissingUnit is an error for a fact whose unitRef doesn't match any unit in the document.
+	ValidationCodeMissingUnit ValidationCode = "missing_unit"
+
+	// ValidationCodeInvalidPeriod is an error for a context whose period breaks the rules of section 4.7.2 of the spec. See Period.Validate().
+	ValidationCodeInvalidPeriod ValidationCode = "invalid_period"
+
+	// ValidationCodeInvalidEntity is an error for a context whose entity breaks the rules of section 4.7.3 of the spec:
+	// its identifier must have a scheme and a value, and its segment can't contain XBRL instance elements.
+	ValidationCodeInvalidEntity ValidationCode = "invalid_entity"
+
+	// ValidationCodeInvalidScenario is an error for a context whose scenario contains XBRL instance elements (section 4.7.4 of the spec).
+	ValidationCodeInvalidScenario ValidationCode = "invalid_scenario"
+
+	// ValidationCodeInvalidUnit is an error for a unit that breaks the rules of section 4.8 of the spec. See Unit.Validate().
+	ValidationCodeInvalidUnit ValidationCode = "invalid_unit"
+
+	// ValidationCodePeriodTypeMismatch is an error for a fact whose context has the wrong period type for its concept.
+	// It's only reported for concepts whose period type is known. See ConceptPeriodTypes().
+	ValidationCodePeriodTypeMismatch ValidationCode = "period_type_mismatch"
+
+	// ValidationCodeUnusedContext is a warning for a context that no fact references.
+	ValidationCodeUnusedContext ValidationCode = "unused_context"
+
+	// ValidationCodeUnusedUnit is a warning for a unit that no fact references.
+	ValidationCodeUnusedUnit ValidationCode = "unused_unit"
+)
+
+// ValidationIssue is a single problem found by XBRL.ValidateAll().
+type ValidationIssue struct {
+	Severity Severity
+	Code     ValidationCode
+
+	// Message describes the problem, including the element involved and its position in the document if it's known.
+	Message string
+
+	// Fact, Context and Unit are the elements involved in the issue, and nil if they aren't.
+	// Fact points to the fact in XBRL.Facts.
+	Fact    *Fact
+	Context *Context
+	Unit    *Unit
+
+	// Position is where the element the issue is about is in the document, if it's known.
+	Position Position
+}
+
+// Error implements error, so a single issue can be returned as an error.
+func (i ValidationIssue) Error() string {
+	return i.Message
+}
+
+// ValidationReport contains all the issues found by XBRL.ValidateAll().
+// It implements error, which is what XBRL.Validate() returns when the report has errors.
+type ValidationReport struct {
+	// Issues are the problems that were found, facts first in document order, then contexts and units in document order.
+	Issues []ValidationIssue
+}
+
+// Error returns the message of the first error (or warning if there are no errors) and the number of other issues.
+func (r ValidationReport) Error() string {
+	if len(r.Issues) == 0 {
+		return "no validation issues"
+	}
+
+	first := r.Issues[0]
+	for _, issue := range r.Issues {
+		if issue.Severity == SeverityError {
+			first = issue
+			break
+		}
+	}
+
+	switch len(r.Issues) {
+	case 1:
+		return first.Message
+	case 2:
+		return first.Message + " (and 1 more issue)"
+	default:
+		return fmt.Sprintf("%s (and %d more issues)", first.Message, len(r.Issues)-1)
+	}
+}
+
+// HasErrors returns true if any of the issues is a SeverityError.
+func (r ValidationReport) HasErrors() bool {
+	return len(r.Errors()) > 0
+}
+
+// Errors returns the issues with SeverityError.
+func (r ValidationReport) Errors() []ValidationIssue {
+	return r.withSeverity(SeverityError)
+}
+
+// Warnings returns the issues with SeverityWarning.
+func (r ValidationReport) Warnings() []ValidationIssue {
+	return r.withSeverity(SeverityWarning)
+}
+
+func (r ValidationReport) withSeverity(severity Severity) []ValidationIssue {
+	var issues []ValidationIssue
+	for _, issue := range r.Issues {
+		if issue.Severity == severity {
+			issues = append(issues, issue)
+		}
+	}
+
+	return issues
+}
+
+// String returns every issue on its own line, prefixed by its severity and code.
+func (r ValidationReport) String() string {
+	var builder strings.Builder
+	for _, issue := range r.Issues {
+		fmt.Fprintf(&builder, "%s %s: %s\n", issue.Severity, issue.Code, issue.Message)
+	}
+
+	return builder.String()
+}
+
+// add appends an issue about fact, context or unit (any of which can be nil) to the report.
+func (r *ValidationReport) add(severity Severity, code ValidationCode, fact *Fact, context *Context, unit *Unit, format string, args ...interface{}) {
+	issue := ValidationIssue{
+		Severity: severity,
+		Code:     code,
+		Message:  fmt.Sprintf(format, args...),
+		Fact:     fact,
+		Context:  context,
+		Unit:     unit,
+	}
+
+	switch {
+	case fact != nil:
+		issue.Position = fact.Position
+	case context != nil:
+		issue.Position = context.Position
+	case unit != nil:
+		issue.Position = unit.Position
+	}
+
+	r.Issues = append(r.Issues, issue)
+}
+
+// ValidationOption changes what XBRL.Validate() and XBRL.ValidateAll() check.
+type ValidationOption func(options *validationOptions)
+
+type validationOptions struct {
+	conceptPeriodTypes map[xml.Name]PeriodType
+}
+
+// ConceptPeriodTypes gives the period types of concepts, keyed by their QName.ExpandedName().
+// The period type of a concept is its xbrli:periodType attribute in the taxonomy schema, which isn't part of the document itself.
+//
+// With this option, facts of PeriodTypeInstant concepts must reference a context with an instant period,
+// and facts of PeriodTypeDuration concepts a context with a duration or forever period, as section 5.1.1.1 of the spec requires.
+// Facts of concepts that aren't in the map aren't checked.
+// https://www.xbrl.org/Specification/XBRL-2.1/REC-2003-12-31/XBRL-2.1-REC-2003-12-31+corrected-errata-2013-02-20.html#_5.1.1.1
+func ConceptPeriodTypes(periodTypes map[xml.Name]PeriodType) ValidationOption {
+	return func(options *validationOptions) {
+		options.conceptPeriodTypes = periodTypes
+	}
+}
+
+// Validate checks that all Facts are valid and reference contexts and units that also exist, and that the contexts and units are valid.
+// It returns a ValidationReport with all the issues (including warnings) if there's at least one error, and nil otherwise.
+// See ValidateAll() to get the warnings of a valid document too.
+func (x XBRL) Validate(options ...ValidationOption) error {
+	report := x.ValidateAll(options...)
+	if !report.HasErrors() {
+		return nil
+	}
+
+	return report
+}
+
+// IsValid validates the Facts in this struct and returns true if no error was found.
+func (x XBRL) IsValid() bool {
+	return x.Validate() == nil
+}
+
+// ValidateAll checks the whole document and returns every issue it found, instead of stopping at the first one.
+func (x XBRL) ValidateAll(options ...ValidationOption) ValidationReport {
+	var validationOptions validationOptions
+	for _, option := range options {
+		option(&validationOptions)
+	}
+
+	var report ValidationReport
+	usedContexts := make(map[string]bool, len(x.ContextsByID))
+	usedUnits := make(map[string]bool, len(x.UnitsByID))
+
+	for index := range x.Facts {
+		fact := &x.Facts[index]
+		if !fact.IsValid() {
+			report.add(SeverityError, ValidationCodeInvalidFact, fact, nil, nil, "invalid fact: %s", fact.describe())
+		}
+
+		usedContexts[fact.ContextRef] = true
+		if context, exists := x.ContextsByID[fact.ContextRef]; !exists {
+			report.add(SeverityError, ValidationCodeMissingContext, fact, nil, nil,
+				"fact %s references non-existent context: %s", fact.describe(), fact.ContextRef)
+		} else if periodType, known := validationOptions.conceptPeriodTypes[factConcept(*fact).ExpandedName()]; known {
+			if !periodTypeFits(periodType, context.Period.Type()) {
+				report.add(SeverityError, ValidationCodePeriodTypeMismatch, fact, nil, nil,
+					"fact %s of a concept with period type %s references context %s with period type %s",
+					fact.describe(), periodType, context.ID, context.Period.Type())
+			}
+		}
+
+		if fact.UnitRef != nil {
+			usedUnits[*fact.UnitRef] = true
+			if _, exists := x.UnitsByID[*fact.UnitRef]; !exists {
+				report.add(SeverityError, ValidationCodeMissingUnit, fact, nil, nil,
+					"fact %s references non-existent unit: %s", fact.describe(), *fact.UnitRef)
+			}
+		}
+	}
+
+	for _, context := range x.sortedContexts() {
+		report.addContextIssues(context)
+		if !usedContexts[context.ID] {
+			report.add(SeverityWarning, ValidationCodeUnusedContext, nil, context, nil,
+				"context %s%s is not referenced by any fact", context.ID, describePosition(context.Position))
+		}
+	}
+
+	for _, unit := range x.sortedUnits() {
+		if err := unit.Validate(); err != nil {
+			report.add(SeverityError, ValidationCodeInvalidUnit, nil, nil, unit, "unit %s%s is an %v", unit.ID, describePosition(unit.Position), err)
+		}
+
+		if !usedUnits[unit.ID] {
+			report.add(SeverityWarning, ValidationCodeUnusedUnit, nil, nil, unit,
+				"unit %s%s is not referenced by any fact", unit.ID, describePosition(unit.Position))
+		}
+	}
+
+	return report
+}
+
+// periodTypeFits returns true if a context with the period type actual can be used by facts of a concept with the period type expected.
+// Duration concepts can be reported for forever periods too. A context whose period is invalid is already reported on its own.
+func periodTypeFits(expected, actual PeriodType) bool {
+	switch {
+	case actual == PeriodTypeInvalid || expected == actual:
+		return true
+	case expected == PeriodTypeDuration:
+		return actual == PeriodTypeForever
+	default:
+		return false
+	}
+}
+
+// addContextIssues adds the issues with the period, entity and scenario of context to the report.
+// https://www.xbrl.org/Specification/XBRL-2.1/REC-2003-12-31/XBRL-2.1-REC-2003-12-31+corrected-errata-2013-02-20.html#_4.7
+func (r *ValidationReport) addContextIssues(context *Context) {
+	description := context.ID + describePosition(context.Position)
+	if err := context.Period.Validate(); err != nil {
+		r.add(SeverityError, ValidationCodeInvalidPeriod, nil, context, nil, "context %s has an %v", description, err)
+	}
+
+	identifier := context.Entity.Identifier
+	if strings.TrimSpace(identifier.Scheme) == "" {
+		r.add(SeverityError, ValidationCodeInvalidEntity, nil, context, nil, "context %s has an entity identifier without a scheme", description)
+	}
+
+	if collapseWhitespace(identifier.Value) == "" {
+		r.add(SeverityError, ValidationCodeInvalidEntity, nil, context, nil, "context %s has an empty entity identifier", description)
+	}
+
+	for _, segment := range context.Entity.Segments {
+		if segment.XMLName.Space == NamespaceXBRLI {
+			r.add(SeverityError, ValidationCodeInvalidEntity, nil, context, nil,
+				"context %s has an XBRL instance element in its segment: %s", description, segment.XMLName.Local)
+		}
+	}
+
+	for _, segment := range context.Scenario {
+		if segment.XMLName.Space == NamespaceXBRLI {
+			r.add(SeverityError, ValidationCodeInvalidScenario, nil, context, nil,
+				"context %s has an XBRL instance element in its scenario: %s", description, segment.XMLName.Local)
+		}
+	}
+}
+
+// sortedContexts returns pointers to copies of the contexts in document order, or by ID if their positions aren't known.
+func (x XBRL) sortedContexts() []*Context {
+	contexts := make([]*Context, 0, len(x.ContextsByID))
+	for _, context := range x.ContextsByID {
+		context := context
+		contexts = append(contexts, &context)
+	}
+
+	sort.Slice(contexts, func(i, j int) bool {
+		if contexts[i].Position.Offset != contexts[j].Position.Offset {
+			return contexts[i].Position.Offset < contexts[j].Position.Offset
+		}
+
+		return contexts[i].ID < contexts[j].ID
+	})
+
+	return contexts
+}
+
+// sortedUnits returns pointers to copies of the units in document order, or by ID if their positions aren't known.
+func (x XBRL) sortedUnits() []*Unit {
+	units := make([]*Unit, 0, len(x.UnitsByID))
+	for _, unit := range x.UnitsByID {
+		unit := unit
+		units = append(units, &unit)
+	}
+
+	sort.Slice(units, func(i, j int) bool {
+		if units[i].Position.Offset != units[j].Position.Offset {
+			return units[i].Position.Offset < units[j].Position.Offset
+		}
+
+		return units[i].ID < units[j].ID
+	})
+
+	return units
+}
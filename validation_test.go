@@ -0,0 +1,294 @@
+package xbrl
+
+import (
+	"encoding/xml"
+	"errors"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestValidateAll(t *testing.T) {
+	t.Run("every issue", func(t *testing.T) {
+		// language=xml
+		const doc = `<xbrl>
+    <context id="c1"><entity><identifier scheme="http://www.sec.gov/CIK">0000320193</identifier></entity><period><instant>2021-04-16</instant></period></context>
+    <context id="unused"><entity><identifier scheme="http://www.sec.gov/CIK">0000320193</identifier></entity><period><instant>2021-04-16</instant></period></context>
+    <unit id="u1"><measure>shares</measure></unit>
+    <unit id="u2"><measure>pure</measure></unit>
+    <ci:assets id="f1" contextRef="c1" unitRef="u1" decimals="0">727</ci:assets>
+    <ci:liabilities id="f2" contextRef="c2" unitRef="u1">1</ci:liabilities>
+    <ci:equity id="f3" contextRef="c1" unitRef="u3" decimals="0">2</ci:equity>
+</xbrl>`
+
+		var content XBRL
+		require.NoError(t, xml.Unmarshal([]byte(doc), &content))
+
+		report := content.ValidateAll()
+		require.Len(t, report.Issues, 5)
+
+		var codes []ValidationCode
+		for _, issue := range report.Issues {
+			codes = append(codes, issue.Code)
+		}
+
+		assert.Equal(t, []ValidationCode{
+			ValidationCodeInvalidFact,
+			ValidationCodeMissingContext,
+			ValidationCodeMissingUnit,
+			ValidationCodeUnusedContext,
+			ValidationCodeUnusedUnit,
+		}, codes)
+
+		invalid := report.Issues[0]
+		assert.Equal(t, SeverityError, invalid.Severity)
+		require.NotNil(t, invalid.Fact)
+		assert.Same(t, &content.Facts[1], invalid.Fact)
+		assert.Nil(t, invalid.Context)
+		assert.Nil(t, invalid.Unit)
+		assert.Equal(t, Position{Line: 7, Column: 5, Offset: 520}, invalid.Position)
+		assert.Equal(t, "invalid fact: ci:liabilities (id f2) at line 7, column 5 (offset 520)", invalid.Message)
+
+		unusedContext := report.Issues[3]
+		assert.Equal(t, SeverityWarning, unusedContext.Severity)
+		require.NotNil(t, unusedContext.Context)
+		assert.Equal(t, "unused", unusedContext.Context.ID)
+		assert.Equal(t, content.ContextsByID["unused"].Position, unusedContext.Position)
+
+		unusedUnit := report.Issues[4]
+		require.NotNil(t, unusedUnit.Unit)
+		assert.Equal(t, "u2", unusedUnit.Unit.ID)
+		assert.Equal(t, "unit u2 at line 5, column 5 (offset 390) is not referenced by any fact", unusedUnit.Message)
+
+		assert.True(t, report.HasErrors())
+		assert.Len(t, report.Errors(), 3)
+		assert.Len(t, report.Warnings(), 2)
+	})
+
+	t.Run("contexts", func(t *testing.T) {
+		// language=xml
+		const doc = `<xbrl xmlns:xbrli="http://www.xbrl.org/2003/instance" xmlns:xbrldi="http://xbrl.org/2006/xbrldi">
+    <context id="reversed">
+        <entity><identifier scheme="http://www.sec.gov/CIK">0000320193</identifier></entity>
+        <period><startDate>2021-03-27</startDate><endDate>2020-12-27</endDate></period>
+    </context>
+    <context id="malformed">
+        <entity><identifier scheme="http://www.sec.gov/CIK">0000320193</identifier></entity>
+        <period><instant>March 27, 2021</instant></period>
+    </context>
+    <context id="no-period">
+        <entity><identifier scheme="http://www.sec.gov/CIK">0000320193</identifier></entity>
+    </context>
+    <context id="no-scheme">
+        <entity><identifier> </identifier></entity>
+        <period><forever/></period>
+    </context>
+    <context id="instance-elements">
+        <entity>
+            <identifier scheme="http://www.sec.gov/CIK">0000320193</identifier>
+            <segment><xbrli:unit id="u1"><xbrli:measure>pure</xbrli:measure></xbrli:unit></segment>
+        </entity>
+        <period><instant>2021-03-27</instant></period>
+        <scenario><xbrli:context id="nested"/></scenario>
+    </context>
+    <ci:assets contextRef="reversed" unitRef="u1" decimals="0">1</ci:assets>
+    <ci:assets contextRef="malformed" unitRef="u1" decimals="0">1</ci:assets>
+    <ci:assets contextRef="no-period" unitRef="u1" decimals="0">1</ci:assets>
+    <ci:assets contextRef="no-scheme" unitRef="u1" decimals="0">1</ci:assets>
+    <ci:assets contextRef="instance-elements" unitRef="u1" decimals="0">1</ci:assets>
+    <unit id="u1"><measure>pure</measure></unit>
+</xbrl>`
+
+		var content XBRL
+		require.NoError(t, xml.Unmarshal([]byte(doc), &content))
+
+		report := content.ValidateAll()
+		require.Len(t, report.Issues, 7)
+
+		var codes []ValidationCode
+		var contextIDs []string
+		for _, issue := range report.Issues {
+			assert.Equal(t, SeverityError, issue.Severity)
+			require.NotNil(t, issue.Context)
+			assert.Equal(t, issue.Context.Position, issue.Position)
+			codes = append(codes, issue.Code)
+			contextIDs = append(contextIDs, issue.Context.ID)
+		}
+
+		assert.Equal(t, []ValidationCode{
+			ValidationCodeInvalidPeriod,
+			ValidationCodeInvalidPeriod,
+			ValidationCodeInvalidPeriod,
+			ValidationCodeInvalidEntity,
+			ValidationCodeInvalidEntity,
+			ValidationCodeInvalidEntity,
+			ValidationCodeInvalidScenario,
+		}, codes)
+		assert.Equal(t, []string{"reversed", "malformed", "no-period", "no-scheme", "no-scheme", "instance-elements", "instance-elements"}, contextIDs)
+
+		assert.Equal(t, "context reversed at line 2, column 5 (offset 102) has an invalid period: endDate 2020-12-27 isn't after startDate 2021-03-27",
+			report.Issues[0].Message)
+		assert.Equal(t, "context no-scheme at line 13, column 5 (offset 659) has an entity identifier without a scheme", report.Issues[3].Message)
+		assert.Equal(t, "context no-scheme at line 13, column 5 (offset 659) has an empty entity identifier", report.Issues[4].Message)
+		assert.Contains(t, report.Issues[5].Message, "has an XBRL instance element in its segment: unit")
+		assert.Contains(t, report.Issues[6].Message, "has an XBRL instance element in its scenario: context")
+	})
+
+	t.Run("units", func(t *testing.T) {
+		// language=xml
+		const doc = `<xbrl xmlns:iso4217="http://www.xbrl.org/2003/iso4217">
+    <context id="c1"><entity><identifier scheme="http://www.sec.gov/CIK">0000320193</identifier></entity><period><instant>2021-03-27</instant></period></context>
+    <unit id="dollars"><measure>iso4217:Dollars</measure></unit>
+    <unit id="usd-per-usd">
+        <measure>shares</measure>
+        <divide>
+            <unitNumerator><measure>iso4217:USD</measure></unitNumerator>
+            <unitDenominator><measure>iso4217:USD</measure></unitDenominator>
+        </divide>
+    </unit>
+    <ci:assets contextRef="c1" unitRef="dollars" decimals="0">1</ci:assets>
+    <ci:ratio contextRef="c1" unitRef="usd-per-usd" decimals="0">1</ci:ratio>
+</xbrl>`
+
+		var content XBRL
+		require.NoError(t, xml.Unmarshal([]byte(doc), &content))
+
+		report := content.ValidateAll()
+		require.Len(t, report.Issues, 2)
+
+		for _, issue := range report.Issues {
+			assert.Equal(t, ValidationCodeInvalidUnit, issue.Code)
+			assert.Equal(t, SeverityError, issue.Severity)
+			require.NotNil(t, issue.Unit)
+			assert.Equal(t, issue.Unit.Position, issue.Position)
+		}
+
+		assert.Equal(t, "dollars", report.Issues[0].Unit.ID)
+		assert.Contains(t, report.Issues[0].Message, "is an invalid unit: iso4217:Dollars isn't an ISO 4217 currency code")
+		assert.Equal(t, "usd-per-usd", report.Issues[1].Unit.ID)
+		assert.Contains(t, report.Issues[1].Message, "is an invalid unit: both measures and a divide")
+	})
+
+	t.Run("concept period types", func(t *testing.T) {
+		// language=xml
+		const doc = `<xbrl xmlns:ci="http://www.xbrl.org/us/gaap/ci/2003/usfr-ci-2003">
+    <context id="instant"><entity><identifier scheme="http://www.sec.gov/CIK">0000320193</identifier></entity><period><instant>2021-03-27</instant></period></context>
+    <context id="duration"><entity><identifier scheme="http://www.sec.gov/CIK">0000320193</identifier></entity><period><startDate>2020-12-27</startDate><endDate>2021-03-27</endDate></period></context>
+    <context id="forever"><entity><identifier scheme="http://www.sec.gov/CIK">0000320193</identifier></entity><period><forever/></period></context>
+    <unit id="usd"><measure>iso4217:USD</measure></unit>
+    <ci:assets id="f1" contextRef="instant" unitRef="usd" decimals="0">1</ci:assets>
+    <ci:assets id="f2" contextRef="duration" unitRef="usd" decimals="0">1</ci:assets>
+    <ci:revenue id="f3" contextRef="duration" unitRef="usd" decimals="0">1</ci:revenue>
+    <ci:revenue id="f4" contextRef="forever" unitRef="usd" decimals="0">1</ci:revenue>
+    <ci:revenue id="f5" contextRef="instant" unitRef="usd" decimals="0">1</ci:revenue>
+    <ci:description id="f6" contextRef="instant">Apple Inc.</ci:description>
+</xbrl>`
+
+		var content XBRL
+		require.NoError(t, xml.Unmarshal([]byte(doc), &content))
+		require.NoError(t, content.Validate())
+
+		const namespace = "http://www.xbrl.org/us/gaap/ci/2003/usfr-ci-2003"
+		periodTypes := ConceptPeriodTypes(map[xml.Name]PeriodType{
+			{Space: namespace, Local: "assets"}:  PeriodTypeInstant,
+			{Space: namespace, Local: "revenue"}: PeriodTypeDuration,
+		})
+
+		err := content.Validate(periodTypes)
+		require.Error(t, err)
+
+		report := content.ValidateAll(periodTypes)
+		require.Len(t, report.Issues, 2)
+
+		mismatch := report.Issues[0]
+		assert.Equal(t, ValidationCodePeriodTypeMismatch, mismatch.Code)
+		assert.Equal(t, SeverityError, mismatch.Severity)
+		assert.Same(t, &content.Facts[1], mismatch.Fact)
+		assert.Equal(t, "fact ci:assets (id f2) at line 7, column 5 (offset 729) of a concept with period type instant references context duration with period type duration",
+			mismatch.Message)
+
+		assert.Same(t, &content.Facts[4], report.Issues[1].Fact)
+	})
+
+	t.Run("concepts without a period type aren't checked", func(t *testing.T) {
+		// language=xml
+		const doc = `<xbrl xmlns:ci="http://www.xbrl.org/us/gaap/ci/2003/usfr-ci-2003">
+    <context id="instant"><entity><identifier scheme="http://www.sec.gov/CIK">0000320193</identifier></entity><period><instant>2021-03-27</instant></period></context>
+    <context id="duration"><entity><identifier scheme="http://www.sec.gov/CIK">0000320193</identifier></entity><period><startDate>2020-12-27</startDate><endDate>2021-03-27</endDate></period></context>
+    <ci:description contextRef="instant">Apple Inc.</ci:description>
+    <ci:description contextRef="duration">Apple Inc.</ci:description>
+</xbrl>`
+
+		var content XBRL
+		require.NoError(t, xml.Unmarshal([]byte(doc), &content))
+
+		report := content.ValidateAll(ConceptPeriodTypes(map[xml.Name]PeriodType{
+			{Space: "http://www.xbrl.org/us/gaap/ci/2003/usfr-ci-2003", Local: "assets"}: PeriodTypeInstant,
+		}))
+		assert.Empty(t, report.Issues)
+	})
+
+	t.Run("valid document", func(t *testing.T) {
+		f, err := os.Open("test_data/aapl-20210327_htm.xml")
+		require.NoError(t, err)
+		defer f.Close()
+
+		var content XBRL
+		require.NoError(t, xml.NewDecoder(f).Decode(&content))
+
+		report := content.ValidateAll()
+		assert.False(t, report.HasErrors())
+		assert.Empty(t, report.Errors())
+	})
+}
+
+func TestValidate(t *testing.T) {
+	t.Run("returns the report", func(t *testing.T) {
+		xbrlBytes, err := os.ReadFile("test_data/invalid_xbrl.xml")
+		require.NoError(t, err)
+
+		var content XBRL
+		require.NoError(t, xml.Unmarshal(xbrlBytes, &content))
+
+		err = content.Validate()
+		require.Error(t, err)
+		assert.False(t, content.IsValid())
+
+		var report ValidationReport
+		require.True(t, errors.As(err, &report))
+		require.Len(t, report.Issues, 2)
+		assert.Equal(t, ValidationCodeMissingContext, report.Issues[0].Code)
+		assert.Equal(t, ValidationCodeMissingUnit, report.Issues[1].Code)
+
+		assert.Equal(t, "fact ci:assets at line 3, column 5 (offset 117) references non-existent context: c1 (and 1 more issue)", err.Error())
+		assert.Equal(t, "error missing_context: fact ci:assets at line 3, column 5 (offset 117) references non-existent context: c1\n"+
+			"error missing_unit: fact ci:assets at line 3, column 5 (offset 117) references non-existent unit: u1\n", report.String())
+	})
+
+	t.Run("warnings only", func(t *testing.T) {
+		// language=xml
+		const doc = `<xbrl>
+    <context id="c1"><entity><identifier scheme="http://www.sec.gov/CIK">0000320193</identifier></entity><period><instant>2021-04-16</instant></period></context>
+    <unit id="u1"><measure>shares</measure></unit>
+</xbrl>`
+
+		var content XBRL
+		require.NoError(t, xml.Unmarshal([]byte(doc), &content))
+
+		assert.NoError(t, content.Validate())
+		assert.True(t, content.IsValid())
+
+		report := content.ValidateAll()
+		assert.Len(t, report.Warnings(), 2)
+		assert.Equal(t, "context c1 at line 2, column 5 (offset 11) is not referenced by any fact (and 1 more issue)", report.Error())
+	})
+
+	t.Run("no issues", func(t *testing.T) {
+		var report ValidationReport
+		assert.False(t, report.HasErrors())
+		assert.Equal(t, "no validation issues", report.Error())
+		assert.Empty(t, report.String())
+	})
+}
@@ -5,13 +5,8 @@ import (
 	"fmt"
 )
 
-// NotImplemented represents an expected element in the XBRL that isn't handled yet, but should not be considered a Fact.
-type NotImplemented []*struct{}
-
 // RawXBRL represents the XML structure of an XBRL document.
-// This is not a feature complete XBRL parser!
-// See the fields of type NotImplemented for an idea of what's missing.
-// Also note that this struct doesn't support Tuple facts (https://www.xbrl.org/Specification/XBRL-2.1/REC-2003-12-31/XBRL-2.1-REC-2003-12-31+corrected-errata-2013-02-20.html#_4.9)
+// This is not a feature complete XBRL parser! The taxonomy referenced by SchemaRefs and LinkbaseRefs is not loaded.
 //
 // You can use this struct directly, but XBRL is structured in a more convenient way.
 // See the comment on XBRL for more info.
@@ -19,15 +14,174 @@ type RawXBRL struct {
 	Contexts []Context `xml:"context"`
 	Units    []Unit    `xml:"unit"`
 
+	// Facts contains every item in the document in document order, including the items inside of tuples.
 	Facts []Fact `xml:",any"`
 
-	// The fields below are not properly implemented, but need to be here so they aren't lumped into the `Facts` slice.
+	// Tuples contains the top-level tuples in the document. Nested tuples can be found through their parent's Children.
+	Tuples []*Tuple `xml:"-"`
+
+	FootnoteLinks []FootnoteLink `xml:"footnoteLink"`
+
+	SchemaRefs   []SchemaRef   `xml:"schemaRef"`
+	LinkbaseRefs []LinkbaseRef `xml:"linkbaseRef"`
+	RoleRefs     []RoleRef     `xml:"roleRef"`
+	ArcRoleRefs  []ArcRoleRef  `xml:"arcroleRef"`
+}
+
+// UnmarshalXML implements xml.Unmarshaler.
+// It decodes contexts and units by their element names, and everything that isn't a known XBRL element as either a Fact or a Tuple.
+func (r *RawXBRL) UnmarshalXML(d *xml.Decoder, start xml.StartElement) error {
+	scope := namespaceScope{}.with(start)
+	strs := newStringInterner()
+	for {
+		pos := decoderPosition(d)
+		token, err := d.Token()
+		if err != nil {
+			return err
+		}
+
+		switch t := token.(type) {
+		case xml.StartElement:
+			if err := r.decodeElement(d, t, pos, scope, strs); err != nil {
+				return err
+			}
+		case xml.EndElement:
+			r.linkTupleFacts(r.Tuples)
+			return nil
+		}
+	}
+}
+
+// decodeElement decodes a top-level element of an XBRL document into the matching field of r, and pos is the position of its start tag.
+// The QNames in facts, units and contexts are resolved against scope, which holds the namespaces declared on the enclosing elements,
+// and strs interns the strings that repeat across the facts of the document.
+func (r *RawXBRL) decodeElement(d *xml.Decoder, start xml.StartElement, pos Position, scope namespaceScope, strs *stringInterner) error {
+	switch start.Name.Local {
+	case "context":
+		var context Context
+		if err := d.DecodeElement(&context, &start); err != nil {
+			return fmt.Errorf("context at %s: %w", pos, err)
+		}
+
+		context.Position = pos
+		context.resolveNames(scope.with(start))
+		r.Contexts = append(r.Contexts, context)
+	case "unit":
+		var unit Unit
+		if err := d.DecodeElement(&unit, &start); err != nil {
+			return fmt.Errorf("unit at %s: %w", pos, err)
+		}
+
+		unit.Position = pos
+		unit.resolveNames(scope.with(start))
+		r.Units = append(r.Units, unit)
+	case "schemaRef":
+		var ref SchemaRef
+		if err := d.DecodeElement(&ref, &start); err != nil {
+			return err
+		}
+
+		r.SchemaRefs = append(r.SchemaRefs, ref)
+	case "linkbaseRef":
+		var ref LinkbaseRef
+		if err := d.DecodeElement(&ref, &start); err != nil {
+			return err
+		}
+
+		r.LinkbaseRefs = append(r.LinkbaseRefs, ref)
+	case "roleRef":
+		var ref RoleRef
+		if err := d.DecodeElement(&ref, &start); err != nil {
+			return err
+		}
+
+		r.RoleRefs = append(r.RoleRefs, ref)
+	case "arcroleRef":
+		var ref ArcRoleRef
+		if err := d.DecodeElement(&ref, &start); err != nil {
+			return err
+		}
+
+		r.ArcRoleRefs = append(r.ArcRoleRefs, ref)
+	case "footnoteLink":
+		var link FootnoteLink
+		if err := d.DecodeElement(&link, &start); err != nil {
+			return err
+		}
+
+		r.FootnoteLinks = append(r.FootnoteLinks, link)
+	default:
+		tuple, _, err := r.decodeItem(d, start, pos, scope, strs)
+		if err != nil {
+			return err
+		}
+
+		if tuple != nil {
+			r.Tuples = append(r.Tuples, tuple)
+		}
+	}
+
+	return nil
+}
+
+// stringInterner deduplicates strings that repeat a lot in a document, like the contextRef and unitRef of facts,
+// and hands out the pointers for the values of facts in blocks, so that a fact's value doesn't need an allocation of its own.
+// A nil stringInterner doesn't deduplicate anything.
+type stringInterner struct {
+	strings map[string]*string
+	values  []string
+}
+
+// valueBlockSize is how many fact values share an allocation.
+const valueBlockSize = 64
 
-	SchemaRef    NotImplemented `xml:"schemaRef"`
-	LinkbaseRef  NotImplemented `xml:"linkbaseRef"`
-	RoleRef      NotImplemented `xml:"roleRef"`
-	ArcRoleRef   NotImplemented `xml:"arcroleRef"`
-	FootnoteLink NotImplemented `xml:"footnoteLink"`
+// newStringInterner returns an empty stringInterner for decoding a document.
+func newStringInterner() *stringInterner {
+	return &stringInterner{strings: make(map[string]*string)}
+}
+
+// intern returns a pointer to a string equal to value, which is shared by all the calls with the same value.
+func (s *stringInterner) intern(value string) *string {
+	if s != nil {
+		if interned, exists := s.strings[value]; exists {
+			return interned
+		}
+	}
+
+	// Copying value before taking its address keeps it from escaping, so only the first call with a value allocates.
+	interned := value
+	if s != nil {
+		s.strings[value] = &interned
+	}
+
+	return &interned
+}
+
+// value returns a pointer to a string equal to value that isn't shared, but is allocated together with the values before it.
+func (s *stringInterner) value(value string) *string {
+	if s == nil {
+		copied := value
+		return &copied
+	}
+
+	if len(s.values) == cap(s.values) {
+		s.values = make([]string, 0, valueBlockSize)
+	}
+
+	s.values = append(s.values, value)
+	return &s.values[len(s.values)-1]
+}
+
+// sharedBools are the values that the Nil field of facts and tuples point to, so that they don't need an allocation of their own.
+var sharedBools = [2]bool{false, true}
+
+// sharedBool returns a pointer to a bool equal to value, which is shared by all the calls with the same value.
+func sharedBool(value bool) *bool {
+	if value {
+		return &sharedBools[1]
+	}
+
+	return &sharedBools[0]
 }
 
 // XBRL contains maps for contexts and units so they can be accessed easier when looping through facts.
@@ -38,25 +192,121 @@ type XBRL struct {
 	UnitsByID    map[string]Unit
 
 	Facts []Fact
+
+	// Tuples contains the top-level tuples in the document. See RawXBRL.Tuples.
+	Tuples []*Tuple
+
+	// FootnotesByFactID contains the footnotes attached to each fact, keyed by the fact's ID.
+	// Facts without an ID can't have footnotes. See XBRL.Footnotes().
+	FootnotesByFactID map[string][]Footnote
+
+	SchemaRefs   []SchemaRef
+	LinkbaseRefs []LinkbaseRef
+	RoleRefs     []RoleRef
+	ArcRoleRefs  []ArcRoleRef
+}
+
+// ProcessOption changes how NewProcessedXBRL processes a RawXBRL.
+type ProcessOption func(options *processOptions)
+
+type processOptions struct {
+	mergeContexts bool
+	mergeUnits    bool
+}
+
+// MergeEquivalentContexts makes NewProcessedXBRL merge the contexts that are s-equal (see Context.Equal()),
+// so facts about the same entity, period and dimensions reference the same context even if the document has several copies of it.
+//
+// The first context of each set of equal contexts (in document order) is kept in ContextsByID, and the others are left out.
+// Facts referencing one of the others reference the kept context instead.
+func MergeEquivalentContexts() ProcessOption {
+	return func(options *processOptions) {
+		options.mergeContexts = true
+	}
+}
+
+// MergeEquivalentUnits makes NewProcessedXBRL merge the units that are u-equal (see Unit.Equal()),
+// the same way MergeEquivalentContexts merges contexts.
+func MergeEquivalentUnits() ProcessOption {
+	return func(options *processOptions) {
+		options.mergeUnits = true
+	}
 }
 
 // NewProcessedXBRL constructs a XBRL struct from a RawXBRL struct.
-func NewProcessedXBRL(raw RawXBRL) XBRL {
+// The facts are shared with raw, so if contexts or units are merged (see ProcessOption), the references of the facts change in raw too.
+func NewProcessedXBRL(raw RawXBRL, options ...ProcessOption) XBRL {
+	var processOptions processOptions
+	for _, option := range options {
+		option(&processOptions)
+	}
+
 	contextsByID := make(map[string]Context, len(raw.Contexts))
 	unitsByID := make(map[string]Unit, len(raw.Units))
 
+	// mergedContexts and mergedUnits map the IDs of the contexts and units that were merged to the ID of the one they were merged into.
+	mergedContexts := make(map[string]string)
+	mergedUnits := make(map[string]*string)
+
+	contextIDsByKey := make(map[string]string)
 	for _, context := range raw.Contexts {
+		if processOptions.mergeContexts {
+			key := context.Key()
+			if id, exists := contextIDsByKey[key]; exists && id != context.ID {
+				mergedContexts[context.ID] = id
+				continue
+			}
+
+			contextIDsByKey[key] = context.ID
+		}
+
 		contextsByID[context.ID] = context
 	}
 
+	unitIDsByKey := make(map[string]*string)
 	for _, unit := range raw.Units {
+		if processOptions.mergeUnits {
+			key := unit.Key()
+			if id, exists := unitIDsByKey[key]; exists && *id != unit.ID {
+				mergedUnits[unit.ID] = id
+				continue
+			}
+
+			id := unit.ID
+			unitIDsByKey[key] = &id
+		}
+
 		unitsByID[unit.ID] = unit
 	}
 
+	if len(mergedContexts) > 0 || len(mergedUnits) > 0 {
+		for index := range raw.Facts {
+			fact := &raw.Facts[index]
+			if id, merged := mergedContexts[fact.ContextRef]; merged {
+				fact.ContextRef = id
+			}
+
+			// UnitRef can be shared with other facts, so it's replaced rather than changed.
+			if fact.UnitRef != nil {
+				if id, merged := mergedUnits[*fact.UnitRef]; merged {
+					fact.UnitRef = id
+				}
+			}
+		}
+	}
+
 	return XBRL{
 		ContextsByID: contextsByID,
 		UnitsByID:    unitsByID,
 		Facts:        raw.Facts,
+		Tuples:       raw.Tuples,
+
+		FootnotesByFactID: footnotesByFactID(raw.FootnoteLinks),
+
+		SchemaRefs:   raw.SchemaRefs,
+		LinkbaseRefs: raw.LinkbaseRefs,
+		RoleRefs:     raw.RoleRefs,
+		ArcRoleRefs:  raw.ArcRoleRefs,
 	}
 }
 
@@ -72,29 +322,11 @@ func (x *XBRL) UnmarshalXML(d *xml.Decoder, start xml.StartElement) error {
 	return nil
 }
 
-// Validate checks that all Facts are valid and reference contexts and units that also exist.
-// Note that since this parser does not properly handle Tuple elements, it's possible that some malformed Facts were unmarshalled.
-func (x XBRL) Validate() error {
-	for _, fact := range x.Facts {
-		if !fact.IsValid() {
-			return fmt.Errorf("invalid fact: %s:%s", fact.XMLName.Space, fact.XMLName.Local)
-		}
-
-		if _, exists := x.ContextsByID[fact.ContextRef]; !exists {
-			return fmt.Errorf("fact (%s:%s) references non-existent context: %s", fact.XMLName.Space, fact.XMLName.Local, fact.ContextRef)
-		}
-
-		if fact.UnitRef != nil {
-			if _, exists := x.UnitsByID[*fact.UnitRef]; !exists {
-				return fmt.Errorf("fact (%s:%s) references non-existent unit: %s", fact.XMLName.Space, fact.XMLName.Local, *fact.UnitRef)
-			}
-		}
+// Footnotes returns the footnotes attached to a fact, or nil if it has none.
+func (x XBRL) Footnotes(fact Fact) []Footnote {
+	if fact.ID == "" {
+		return nil
 	}
 
-	return nil
-}
-
-// IsValid validates the Facts in this struct and returns true if no error was found.
-func (x XBRL) IsValid() bool {
-	return x.Validate() == nil
+	return x.FootnotesByFactID[fact.ID]
 }
@@ -2,8 +2,10 @@ package xbrl
 
 import (
 	"encoding/xml"
+	"fmt"
 	"io"
 	"os"
+	"strings"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -69,6 +71,7 @@ func TestUnmarshalXBRL(t *testing.T) {
 					Value:  "0000320193",
 				},
 			},
+			Position: Position{Line: 10, Column: 5, Offset: 524},
 		}
 
 		assert.Equal(t, expectedContext, content.ContextsByID["c1"])
@@ -76,7 +79,8 @@ func TestUnmarshalXBRL(t *testing.T) {
 		require.Len(t, content.UnitsByID, 1)
 		expectedUnit := Unit{
 			ID:       "u1",
-			Measures: Measures{{Value: "shares"}},
+			Measures: Measures{{Value: "shares", Name: QName{Space: NamespaceXBRLI, Local: "shares"}}},
+			Position: Position{Line: 22, Column: 5, Offset: 904},
 		}
 
 		assert.Equal(t, expectedUnit, content.UnitsByID["u1"])
@@ -85,15 +89,19 @@ func TestUnmarshalXBRL(t *testing.T) {
 		expectedFacts := []Fact{
 			{
 				XMLName:    xml.Name{Space: "http://www.xbrl.org/us/gaap/ci/2003/usfr-ci-2003", Local: "assets"},
+				Name:       QName{Space: "http://www.xbrl.org/us/gaap/ci/2003/usfr-ci-2003", Local: "assets", Prefix: "ci"},
 				ContextRef: "c1",
 				UnitRef:    stringPtr("u1"),
 				Precision:  stringPtr("3"),
 				ValueStr:   stringPtr("727"),
+				Position:   Position{Line: 19, Column: 5, Offset: 754},
 			},
 			{
 				XMLName:    xml.Name{Space: "fakens", Local: "textItem"},
+				Name:       QName{Local: "textItem", Prefix: "fakens"},
 				ContextRef: "c1",
 				ValueStr:   stringPtr("this is a text item"),
+				Position:   Position{Line: 20, Column: 5, Offset: 828},
 			},
 		}
 
@@ -114,3 +122,44 @@ func TestUnmarshalXBRL(t *testing.T) {
 func stringPtr(str string) *string {
 	return &str
 }
+
+func BenchmarkUnmarshalXBRL(b *testing.B) {
+	for _, name := range []string{"aapl-20210327_htm.xml", "simple_xbrl.xml"} {
+		xbrlBytes, err := os.ReadFile("test_data/" + name)
+		require.NoError(b, err)
+
+		b.Run(name, func(b *testing.B) {
+			b.ReportAllocs()
+			b.SetBytes(int64(len(xbrlBytes)))
+
+			for i := 0; i < b.N; i++ {
+				var content XBRL
+				if err := xml.Unmarshal(xbrlBytes, &content); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+	}
+}
+
+// BenchmarkUnmarshalFacts decodes a document that's all facts, so allocs/op divided by factCount is the cost of a single fact.
+func BenchmarkUnmarshalFacts(b *testing.B) {
+	const factCount = 1000
+
+	var doc strings.Builder
+	doc.WriteString(`<xbrl xmlns:us-gaap="http://fasb.org/us-gaap/2021-01-31">`)
+	for i := 0; i < factCount; i++ {
+		fmt.Fprintf(&doc, `<us-gaap:Revenues contextRef="c%d" unitRef="usd" decimals="-6">%d000000</us-gaap:Revenues>`, i%10, i)
+	}
+	doc.WriteString(`</xbrl>`)
+	xbrlBytes := []byte(doc.String())
+
+	b.ReportAllocs()
+	b.SetBytes(int64(len(xbrlBytes)))
+	for i := 0; i < b.N; i++ {
+		var raw RawXBRL
+		if err := xml.Unmarshal(xbrlBytes, &raw); err != nil {
+			b.Fatal(err)
+		}
+	}
+}